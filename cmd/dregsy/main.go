@@ -82,6 +82,10 @@ func main() {
 	fs := flag.NewFlagSet("dregsy", flag.ContinueOnError)
 	configFile := fs.String("config", "", "path to config file")
 	taskFilter := fs.String("run", "", "task filter regex")
+	lockFile := fs.String("lockfile", "",
+		"path to lockfile for recording source digests of synced images")
+	locked := fs.Bool("locked", false,
+		"sync exactly the images recorded in lockfile")
 
 	if testRound {
 		if len(testArgs) > 0 {
@@ -95,14 +99,19 @@ func main() {
 
 	if len(*configFile) == 0 {
 		version()
-		fmt.Println(
-			"synopsis: dregsy -config={config file} [-run {task name regex}]")
+		fmt.Println("synopsis: dregsy -config={config file} " +
+			"[-run {task name regex}] [-lockfile={lockfile} [-locked]]")
 		exit(1)
 	}
 
+	if *locked && len(*lockFile) == 0 {
+		failOnError(fmt.Errorf("-locked requires -lockfile"))
+	}
+
 	var err error
 	for restart := true; restart; {
-		if restart, err = run(*configFile, *taskFilter); restart {
+		if restart, err = run(
+			*configFile, *taskFilter, *lockFile, *locked); restart {
 			log.Infoln()
 			log.Info("restarting ...")
 			log.Infoln()
@@ -115,7 +124,7 @@ func main() {
 }
 
 //
-func run(configFile, taskFilter string) (bool, error) {
+func run(configFile, taskFilter, lockFile string, locked bool) (bool, error) {
 
 	version()
 
@@ -125,6 +134,10 @@ func run(configFile, taskFilter string) (bool, error) {
 	s, err := sync.New(conf)
 	failOnError(err)
 
+	if lockFile != "" {
+		failOnError(s.UseLock(lockFile, locked))
+	}
+
 	if testRound {
 		testSync <- s
 	}
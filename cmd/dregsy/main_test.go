@@ -464,7 +464,7 @@ func validateAgainstExpectations(th *test.TestHelper, c *sync.SyncConfig,
 		ref := fmt.Sprintf("%s/%s", t.Target.Registry, eRef)
 		tags, err := skopeo.ListAllTags(ref,
 			util.DecodeJSONAuth(t.Target.GetAuth()),
-			"", t.Target.SkipTLSVerify)
+			"", t.Target.SkipTLSVerify, t.Target.GetProxy())
 		th.AssertNoError(err)
 		th.AssertEquivalentSlices(eTags, tags)
 	}
@@ -479,7 +479,7 @@ func validateAgainstTaskMapping(th *test.TestHelper, c *sync.SyncConfig) {
 			ref := fmt.Sprintf("%s%s", t.Target.Registry, m.To)
 			tags, err := skopeo.ListAllTags(ref,
 				util.DecodeJSONAuth(t.Target.GetAuth()),
-				"", t.Target.SkipTLSVerify)
+				"", t.Target.SkipTLSVerify, t.Target.GetProxy())
 			th.AssertNoError(err)
 			th.AssertEquivalentSlices(m.Tags, tags)
 			validatePlatforms(th, ref, t, m)
@@ -491,21 +491,22 @@ func validateAgainstTaskMapping(th *test.TestHelper, c *sync.SyncConfig) {
 func validatePlatforms(th *test.TestHelper, ref string, task *sync.Task,
 	mapping *sync.Mapping) {
 
-	if mapping.Platform == "" {
+	platform := mapping.Platform.Single()
+	if platform == "" {
 		return
 	}
 
 	plts := make(map[string]bool)
 
-	if mapping.Platform == "all" {
+	if platform == "all" {
 		for _, p := range testPlatforms {
 			plts[p] = true
 		}
 	} else {
 		for _, p := range testPlatforms {
-			plts[p] = p == mapping.Platform
+			plts[p] = p == platform
 		}
-		plts[mapping.Platform] = true
+		plts[platform] = true
 	}
 
 	for _, t := range mapping.Tags {
@@ -515,7 +516,7 @@ func validatePlatforms(th *test.TestHelper, ref string, task *sync.Task,
 			info, err := skopeo.Inspect(
 				fmt.Sprintf("%s:%s", ref, t), plt, "{{.Os}}/{{.Architecture}}",
 				util.DecodeJSONAuth(task.Target.GetAuth()),
-				"", task.Target.SkipTLSVerify)
+				"", task.Target.SkipTLSVerify, task.Target.GetProxy())
 			th.AssertNoError(err)
 
 			// FIXME: Skopeo inspect only shows OS and architecture, but not
@@ -527,7 +528,7 @@ func validatePlatforms(th *test.TestHelper, ref string, task *sync.Task,
 			if exp {
 				os, arch, _ = util.SplitPlatform(plt)
 			} else {
-				os, arch, _ = util.SplitPlatform(mapping.Platform)
+				os, arch, _ = util.SplitPlatform(platform)
 			}
 			th.AssertEqual(fmt.Sprintf("%s/%s", os, arch), info)
 		}
@@ -548,7 +549,7 @@ func validateDigests(th *test.TestHelper, c *sync.SyncConfig,
 				info, err := skopeo.Inspect(
 					fmt.Sprintf("%s@%s", ref, d), "", "{{.Digest}}",
 					util.DecodeJSONAuth(t.Target.GetAuth()), "",
-					t.Target.SkipTLSVerify)
+					t.Target.SkipTLSVerify, t.Target.GetProxy())
 				th.AssertNoError(err)
 				th.AssertEqual(d, info)
 			}
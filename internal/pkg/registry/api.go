@@ -0,0 +1,83 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// page size used by listers for paginated APIs
+const apiPageSize = 100
+
+// getJSON sends req via client and decodes the JSON response into out. The
+// response header is returned, for listers to evaluate pagination info.
+func getJSON(client *http.Client, req *http.Request, out interface{}) (
+	http.Header, error) {
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "dregsy")
+
+	log.WithField("url", req.URL.String()).Debug("calling lister API")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to '%s' failed: %s",
+			req.URL.Redacted(), resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("error decoding response from '%s': %v",
+			req.URL.Redacted(), err)
+	}
+
+	return resp.Header, nil
+}
+
+// limitReached checks whether a lister has retrieved enough items
+func limitReached(items []string, maxItems int) bool {
+	return maxItems > 0 && len(items) > maxItems
+}
+
+// nextLink returns the URL of the next page from a `Link` response header as
+// defined in RFC 8288, or an empty string if there is none
+func nextLink(header http.Header) string {
+	for _, l := range header.Values("Link") {
+		for _, link := range strings.Split(l, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+			for _, p := range parts[1:] {
+				if strings.TrimSpace(p) == `rel="next"` {
+					u := strings.TrimSpace(parts[0])
+					return strings.TrimSuffix(strings.TrimPrefix(u, "<"), ">")
+				}
+			}
+		}
+	}
+	return ""
+}
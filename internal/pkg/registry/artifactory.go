@@ -0,0 +1,248 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// newArtifactory creates a lister for a Docker repository in JFrog Artifactory.
+// In mode `catalog` (default), the repository's catalog API is used, in mode
+// `aql`, the manifests in the repository are searched via AQL. The API is
+// expected at `/artifactory` on the registry server, unless apiURL is set. For
+// authentication, token is used as bearer token if set, otherwise the registry
+// credentials. If prefix is set, it is prepended to all listed paths, for use
+// with Artifactory's repository path access method.
+func newArtifactory(reg, apiURL, repo, mode, prefix, token string,
+	insecure bool, creds *auth.Credentials, proxy *util.Proxy) (
+	ListSource, error) {
+
+	if repo == "" {
+		return nil, fmt.Errorf("artifactory lister requires a repository")
+	}
+
+	switch mode {
+	case "":
+		mode = "catalog"
+	case "catalog", "aql":
+	default:
+		return nil, fmt.Errorf("invalid artifactory lister mode '%s'", mode)
+	}
+
+	if apiURL == "" {
+		server := strings.SplitN(reg, ":", 2)[0]
+		apiURL = fmt.Sprintf("https://%s/artifactory", server)
+	}
+
+	return &artifactory{
+		apiURL:   strings.TrimSuffix(apiURL, "/"),
+		repo:     repo,
+		mode:     mode,
+		prefix:   strings.Trim(prefix, "/"),
+		token:    token,
+		insecure: insecure,
+		creds:    creds,
+		proxy:    proxy,
+	}, nil
+}
+
+//
+type artifactory struct {
+	apiURL   string
+	repo     string
+	mode     string
+	prefix   string
+	token    string
+	insecure bool
+	creds    *auth.Credentials
+	proxy    *util.Proxy
+}
+
+//
+type artifactoryCatalog struct {
+	Repositories []string `json:"repositories"`
+}
+
+//
+type artifactoryAQLResult struct {
+	Results []struct {
+		Path string `json:"path"`
+	} `json:"results"`
+}
+
+//
+func (a *artifactory) Retrieve(maxItems int) ([]string, error) {
+
+	if err := a.creds.Refresh(); err != nil {
+		return nil, fmt.Errorf("error refreshing credentials: %v", err)
+	}
+
+	var ret []string
+	var err error
+
+	if a.mode == "aql" {
+		ret, err = a.search(maxItems)
+	} else {
+		ret, err = a.catalog(maxItems)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if a.prefix != "" {
+		for ix, r := range ret {
+			ret[ix] = fmt.Sprintf("%s/%s", a.prefix, r)
+		}
+	}
+
+	return ret, nil
+}
+
+// catalog lists repositories via Artifactory's catalog API. Depending on
+// version, Artifactory may ignore the `last` parameter, or return the last item
+// of the previous page again, so we drop duplicates and stop when a page
+// yields no new items.
+func (a *artifactory) catalog(maxItems int) ([]string, error) {
+
+	var ret []string
+	seen := make(map[string]bool)
+	client := a.proxy.Client(a.insecure)
+	last := ""
+
+	for {
+		q := url.Values{}
+		q.Set("n", strconv.Itoa(apiPageSize))
+		if last != "" {
+			q.Set("last", last)
+		}
+
+		req, err := a.request("GET", fmt.Sprintf(
+			"/api/docker/%s/v2/_catalog?%s", url.PathEscape(a.repo),
+			q.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page artifactoryCatalog
+		if _, err := getJSON(client, req, &page); err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, r := range page.Repositories {
+			if !seen[r] {
+				seen[r] = true
+				ret = append(ret, r)
+				added++
+			}
+		}
+
+		if len(page.Repositories) < apiPageSize || added == 0 ||
+			limitReached(ret, maxItems) {
+			return ret, nil
+		}
+		last = page.Repositories[len(page.Repositories)-1]
+	}
+}
+
+// search lists repositories by searching for image manifests via AQL. AQL
+// returns all results at once, so there is no paging, and the sorted list is
+// cut to maxItems instead.
+func (a *artifactory) search(maxItems int) ([]string, error) {
+
+	query := fmt.Sprintf(`items.find({"repo":%q,"$or":[`+
+		`{"name":"manifest.json"},{"name":"list.manifest.json"}]})`+
+		`.include("path")`, a.repo)
+
+	req, err := a.request("POST", "/api/search/aql",
+		bytes.NewBufferString(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	var res artifactoryAQLResult
+	if _, err := getJSON(a.proxy.Client(a.insecure), req, &res); err != nil {
+		return nil, err
+	}
+
+	// path of a manifest is `{repository path}/{tag}`
+	seen := make(map[string]bool)
+	var ret []string
+	for _, r := range res.Results {
+		if repo := path.Dir(r.Path); repo != "." && !seen[repo] {
+			seen[repo] = true
+			ret = append(ret, repo)
+		}
+	}
+	sort.Strings(ret)
+
+	log.WithField("count", len(ret)).Debug("repositories found via AQL")
+	if maxItems > 0 && len(ret) > maxItems {
+		ret = ret[:maxItems]
+	}
+	return ret, nil
+}
+
+//
+func (a *artifactory) Ping() error {
+	req, err := a.request("GET", "/api/system/ping", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.proxy.Client(a.insecure).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ping failed: %s", resp.Status)
+	}
+	return nil
+}
+
+//
+func (a *artifactory) request(method, p string, body io.Reader) (
+	*http.Request, error) {
+
+	req, err := http.NewRequest(method, a.apiURL+p, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
+	} else if !a.creds.Empty() {
+		req.SetBasicAuth(a.creds.Username(), a.creds.Password())
+	}
+
+	return req, nil
+}
@@ -0,0 +1,125 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestArtifactoryLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var repos []string
+	for ix := 0; ix < 110; ix++ {
+		repos = append(repos, fmt.Sprintf("acme/app-%03d", ix))
+	}
+	sort.Strings(repos)
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+
+			if u, p, ok := r.BasicAuth(); !ok || u != "dev" || p != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			switch r.URL.Path {
+
+			case "/artifactory/api/docker/docker-local/v2/_catalog":
+				// like some Artifactory versions, include the item given
+				// as `last` in the next page
+				n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+				start := sort.SearchStrings(repos, r.URL.Query().Get("last"))
+				end := start + n
+				if end > len(repos) {
+					end = len(repos)
+				}
+				json.NewEncoder(w).Encode(map[string][]string{
+					"repositories": repos[start:end]})
+
+			case "/artifactory/api/search/aql":
+				body, _ := io.ReadAll(r.Body)
+				if !strings.Contains(string(body), `"repo":"docker-local"`) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				var res []map[string]string
+				for _, r := range repos[:3] {
+					for _, tag := range []string{"1.0", "1.1"} {
+						res = append(res,
+							map[string]string{"path": r + "/" + tag})
+					}
+				}
+				json.NewEncoder(w).Encode(
+					map[string][]map[string]string{"results": res})
+
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer server.Close()
+
+	creds, err := auth.NewCredentialsFromBasic("dev", "secret")
+	th.AssertNoError(err)
+
+	list, err := NewRepoList("artifactory.acme.com", false, Artifactory,
+		map[string]string{"url": server.URL + "/artifactory",
+			"repository": "docker-local"}, creds, nil)
+	th.AssertNoError(err)
+	list.SetMaxItems(-1)
+	res, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices(repos, res)
+
+	list, err = NewRepoList("artifactory.acme.com", false, Artifactory,
+		map[string]string{"url": server.URL + "/artifactory",
+			"repository": "docker-local", "mode": "aql",
+			"prefix": "docker-local"}, creds, nil)
+	th.AssertNoError(err)
+	res, err = list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"docker-local/acme/app-000",
+		"docker-local/acme/app-001", "docker-local/acme/app-002"}, res)
+
+	// AQL results are not paged, so need to be cut to maxItems
+	list, err = NewRepoList("artifactory.acme.com", false, Artifactory,
+		map[string]string{"url": server.URL + "/artifactory",
+			"repository": "docker-local", "mode": "aql"}, creds, nil)
+	th.AssertNoError(err)
+	list.SetMaxItems(2)
+	res, err = list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"acme/app-000", "acme/app-001"}, res)
+
+	_, err = NewRepoList("artifactory.acme.com", false, Artifactory,
+		map[string]string{"repository": "docker-local", "mode": "walk"},
+		creds, nil)
+	th.AssertError(err, "invalid artifactory lister mode 'walk'")
+}
@@ -19,7 +19,7 @@ package registry
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"strings"
 
 	"golang.org/x/oauth2"
 
@@ -30,11 +30,12 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
 //
 func newCatalog(reg string, insecure, bearer bool,
-	creds *auth.Credentials) ListSource {
+	creds *auth.Credentials, proxy *util.Proxy) ListSource {
 
 	return &catalog{
 		registry: reg,
@@ -47,6 +48,7 @@ func newCatalog(reg string, insecure, bearer bool,
 		insecure: insecure,
 		bearer:   bearer,
 		creds:    creds,
+		proxy:    proxy,
 	}
 }
 
@@ -57,6 +59,16 @@ type catalog struct {
 	insecure bool
 	bearer   bool
 	creds    *auth.Credentials
+	proxy    *util.Proxy
+	prefix   string
+}
+
+// SetPrefix limits listing to repositories starting with prefix. Since the
+// catalog is usually sorted lexically, we can start paging right before the
+// prefix, and stop once we're past it. Catalogs found to be unsorted are listed
+// in full instead.
+func (c *catalog) SetPrefix(prefix string) {
+	c.prefix = prefix
 }
 
 //
@@ -84,28 +96,68 @@ func (c *catalog) Retrieve(maxItems int) ([]string, error) {
 		}
 	}
 
-	opts := []gocrremote.Option{gocrremote.WithAuth(auth)}
-	if c.insecure {
-		t := http.DefaultTransport.(*http.Transport).Clone()
-		t.TLSClientConfig.InsecureSkipVerify = true
-		opts = append(opts, gocrremote.WithTransport(t))
+	opts := []gocrremote.Option{
+		gocrremote.WithAuth(auth),
+		gocrremote.WithTransport(c.proxy.Transport(c.insecure)),
 	}
 
-	var list []string
-	var last string
+	list, sorted, err := c.list(reg, c.prefix != "", maxItems, opts)
+	if err == nil && !sorted {
+		log.WithField("prefix", c.prefix).Warn(
+			"catalog is not sorted, listing all repositories")
+		list, _, err = c.list(reg, false, maxItems, opts)
+	}
+	return list, err
+}
+
+// list pages through the catalog, collecting the repositories starting with
+// the prefix. With skip set, paging starts right before the prefix, and stops
+// once past it. This only works for a sorted catalog, so when names are found
+// out of order, listing is abandoned and sorted is returned as false.
+func (c *catalog) list(reg gocrname.Registry, skip bool, maxItems int,
+	opts []gocrremote.Option) (list []string, sorted bool, err error) {
+
+	var last, prev string
 	pageSize := 100
 
+	if skip {
+		// `last` is exclusive, so we start from the greatest name sorting
+		// before the prefix; repository names consist of ASCII characters
+		// below DEL
+		n := len(c.prefix) - 1
+		last = c.prefix[:n] + string(rune(c.prefix[n]-1)) + "\x7f"
+	}
+
 	for {
 		res, err := gocrremote.CatalogPage(reg, last, pageSize, opts...)
 		if err != nil {
-			return nil, fmt.Errorf("error getting catalog page: %v", err)
+			return nil, false, fmt.Errorf("error getting catalog page: %v", err)
+		}
+		past := false
+		for _, r := range res {
+			if skip && r < prev {
+				return nil, false, nil
+			}
+			prev = r
+			if strings.HasPrefix(r, c.prefix) {
+				list = append(list, r)
+			} else if r > c.prefix {
+				past = true
+			}
+		}
+		// the whole page is checked for order before stopping, so that an
+		// unsorted catalog is detected even if its first name is past the
+		// prefix
+		if skip && past {
+			log.WithField("prefix", c.prefix).Debug(
+				"reached end of prefix in catalog")
+			return list, true, nil
 		}
 		if len(res) > 0 {
-			list = append(list, res...)
 			last = res[len(res)-1]
 		}
-		if len(res) < pageSize || (maxItems > 0 && len(list) > maxItems) {
-			return list, nil
+		if len(res) < pageSize || limitReached(list, maxItems) {
+			return list, true, nil
 		}
 	}
 }
@@ -113,7 +165,9 @@ func (c *catalog) Retrieve(maxItems int) ([]string, error) {
 //
 func (c *catalog) Ping() error {
 	// TODO: possibly use this to get token for push/pull?
+	ctx := context.WithValue(
+		context.TODO(), oauth2.HTTPClient, c.proxy.Client(c.insecure))
 	_, err := c.conf.PasswordCredentialsToken(
-		context.TODO(), c.creds.Username(), c.creds.Password())
+		ctx, c.creds.Username(), c.creds.Password())
 	return err
 }
@@ -0,0 +1,200 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+//
+func TestCatalogViaProxy(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	// the proxy answers on behalf of the upstream registry, which does not
+	// resolve, so the listing can only succeed when going through the proxy
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			proxied = append(proxied, r.URL.String())
+			if r.URL.Host != "upstream.local" {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			switch r.URL.Path {
+			case "/v2/":
+				w.WriteHeader(http.StatusOK)
+			case "/v2/_catalog":
+				json.NewEncoder(w).Encode(map[string][]string{
+					"repositories": {"library/busybox", "library/alpine"}})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer proxy.Close()
+
+	p, err := util.NewProxy(proxy.URL, "")
+	th.AssertNoError(err)
+
+	list, err := NewRepoList("upstream.local", false, Catalog, nil,
+		&auth.Credentials{}, p)
+	th.AssertNoError(err)
+
+	repos, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices(
+		[]string{"library/busybox", "library/alpine"}, repos)
+	th.AssertTrue(len(proxied) > 0)
+
+	// with the registry on the no-proxy list, the proxy must not be used
+	p, err = util.NewProxy(proxy.URL, ".local")
+	th.AssertNoError(err)
+
+	list, err = NewRepoList("upstream.local", false, Catalog, nil,
+		&auth.Credentials{}, p)
+	th.AssertNoError(err)
+
+	proxied = nil
+	_, err = list.Get()
+	th.AssertNotNil(err)
+	th.AssertEqual(0, len(proxied))
+}
+
+//
+func TestCatalogPrefix(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var repos []string
+	for _, p := range []string{"acme", "acme-legacy", "myproject",
+		"myproject-old", "zeta"} {
+		for ix := 0; ix < 150; ix++ {
+			repos = append(repos, p+"/app-"+strconv.Itoa(ix))
+		}
+	}
+	sort.Strings(repos)
+
+	var served int
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/":
+				w.WriteHeader(http.StatusOK)
+			case "/v2/_catalog":
+				last := r.URL.Query().Get("last")
+				n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+				start := sort.SearchStrings(repos, last)
+				if start < len(repos) && repos[start] == last {
+					start++
+				}
+				end := start + n
+				if end > len(repos) {
+					end = len(repos)
+				}
+				served += end - start
+				json.NewEncoder(w).Encode(map[string][]string{
+					"repositories": repos[start:end]})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer server.Close()
+
+	list, err := NewRepoList(strings.TrimPrefix(server.URL, "http://"),
+		false, Catalog, nil, &auth.Credentials{}, nil)
+	th.AssertNoError(err)
+	list.SetMaxItems(-1)
+
+	res, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqual(len(repos), len(res))
+
+	list.SetPrefix("myproject/")
+	served = 0
+	res, err = list.Get()
+	th.AssertNoError(err)
+	th.AssertEqual(150, len(res))
+	for _, r := range res {
+		th.AssertTrue(strings.HasPrefix(r, "myproject/"))
+	}
+	// paging starts right before, and stops right after the prefix
+	th.AssertTrue(served <= 150+100)
+}
+
+//
+func TestCatalogPrefixUnsorted(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	// names are interleaved, and paging follows that order, like registries
+	// that do not sort their catalog
+	var repos []string
+	for ix := 0; ix < 150; ix++ {
+		for _, p := range []string{"zeta", "myproject", "acme"} {
+			repos = append(repos, p+"/app-"+strconv.Itoa(ix))
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/":
+				w.WriteHeader(http.StatusOK)
+			case "/v2/_catalog":
+				last := r.URL.Query().Get("last")
+				n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+				start := 0
+				for ix, repo := range repos {
+					if repo == last {
+						start = ix + 1
+					}
+				}
+				end := start + n
+				if end > len(repos) {
+					end = len(repos)
+				}
+				json.NewEncoder(w).Encode(map[string][]string{
+					"repositories": repos[start:end]})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer server.Close()
+
+	list, err := NewRepoList(strings.TrimPrefix(server.URL, "http://"),
+		false, Catalog, nil, &auth.Credentials{}, nil)
+	th.AssertNoError(err)
+	list.SetMaxItems(-1)
+	list.SetPrefix("myproject/")
+
+	res, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqual(150, len(res))
+	for _, r := range res {
+		th.AssertTrue(strings.HasPrefix(r, "myproject/"))
+	}
+}
@@ -25,6 +25,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
 //
@@ -56,13 +57,14 @@ type DHRepoDescriptor struct {
 }
 
 //
-func newDockerhub(creds *auth.Credentials) ListSource {
-	return &dockerhub{creds: creds}
+func newDockerhub(creds *auth.Credentials, proxy *util.Proxy) ListSource {
+	return &dockerhub{creds: creds, proxy: proxy}
 }
 
 //
 type dockerhub struct {
 	creds *auth.Credentials
+	proxy *util.Proxy
 }
 
 //
@@ -92,7 +94,7 @@ func (d *dockerhub) Retrieve(maxItems int) ([]string, error) {
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("JWT %s", token.Raw()))
 
-		client := &http.Client{}
+		client := d.proxy.Client(false)
 		resp, err := client.Do(req)
 		if err != nil {
 			return nil, err
@@ -134,7 +136,8 @@ func (d *dockerhub) getToken() (*auth.Token, error) {
 		"password": {d.creds.Password()},
 	}
 
-	resp, err := http.PostForm("https://hub.docker.com/v2/users/login/", vals)
+	resp, err := d.proxy.Client(false).PostForm(
+		"https://hub.docker.com/v2/users/login/", vals)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,235 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// newFile creates a lister that reads repositories from a local file, or from
+// a URL. format is either `yaml` (which includes JSON) or `text`. If not set,
+// it's derived from the file extension, with anything other than `.yaml`,
+// `.yml`, and `.json` being read as text. Each repository may come with a
+// list of tags to sync.
+func newFile(file, u, format string, proxy *util.Proxy) (ListSource, error) {
+
+	if (file == "") == (u == "") {
+		return nil, fmt.Errorf("file lister requires either a path or a url")
+	}
+
+	if format == "" {
+		location := file
+		if u != "" {
+			location = strings.SplitN(u, "?", 2)[0]
+		}
+		switch path.Ext(location) {
+		case ".yaml", ".yml", ".json":
+			format = "yaml"
+		default:
+			format = "text"
+		}
+	}
+
+	switch format {
+	case "json":
+		format = "yaml"
+	case "yaml", "text":
+	default:
+		return nil, fmt.Errorf("invalid file lister format '%s'", format)
+	}
+
+	return &fileList{file: file, url: u, format: format, proxy: proxy}, nil
+}
+
+//
+type fileList struct {
+	file   string
+	url    string
+	format string
+	proxy  *util.Proxy
+	tags   map[string][]string
+}
+
+// Retrieve reads the repository list. maxItems is ignored, since cutting the
+// list short would silently drop approved images.
+func (f *fileList) Retrieve(maxItems int) ([]string, error) {
+
+	data, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string][]string
+	var repos []string
+
+	if f.format == "yaml" {
+		repos, entries, err = parseYAMLList(data)
+	} else {
+		repos, entries, err = parseTextList(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing repository list: %v", err)
+	}
+
+	f.tags = entries
+	return repos, nil
+}
+
+//
+func (f *fileList) Ping() error {
+	_, err := f.read()
+	return err
+}
+
+// Tags returns the tags listed for repo in the last retrieval, if any
+func (f *fileList) Tags(repo string) []string {
+	return f.tags[strings.Trim(repo, "/")]
+}
+
+//
+func (f *fileList) read() ([]byte, error) {
+
+	if f.file != "" {
+		return os.ReadFile(f.file)
+	}
+
+	log.WithField("url", f.url).Debug("fetching repository list")
+
+	req, err := http.NewRequest("GET", f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "dregsy")
+
+	resp, err := f.proxy.Client(false).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching '%s' failed: %s", f.url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseYAMLList parses either a list of `{repo}[:{tag}][@{digest}]` entries,
+// or a map of repositories to lists of tags
+func parseYAMLList(data []byte) ([]string, map[string][]string, error) {
+
+	var list []string
+	if err := yaml.Unmarshal(data, &list); err == nil {
+		return parseEntries(list)
+	}
+
+	var m yaml.MapSlice
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, nil, fmt.Errorf(
+			"expected a list of repositories, or a map of repositories to tags")
+	}
+
+	var repos []string
+	tags := make(map[string][]string)
+
+	for _, item := range m {
+		repo := strings.Trim(fmt.Sprint(item.Key), "/")
+		if _, ok := tags[repo]; !ok {
+			repos = append(repos, repo)
+			tags[repo] = nil
+		}
+		if item.Value == nil {
+			continue
+		}
+		values, ok := item.Value.([]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf(
+				"tags for repository '%s' are not a list", repo)
+		}
+		for _, v := range values {
+			tags[repo] = append(tags[repo], fmt.Sprint(v))
+		}
+	}
+
+	return repos, tags, nil
+}
+
+// parseTextList parses `{repo}[:{tag}][@{digest}]` entries, one per line,
+// skipping empty lines and lines starting with `#`
+func parseTextList(data []byte) ([]string, map[string][]string, error) {
+
+	var list []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		l := strings.TrimSpace(scanner.Text())
+		if l != "" && !strings.HasPrefix(l, "#") {
+			list = append(list, l)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return parseEntries(list)
+}
+
+// parseEntries parses `{repo}[:{tag}][@{digest}]` entries, collecting the tags
+// of each repository. A digest is split off first, since it contains a colon
+// itself, and kept with the tag as in verbatim tags.
+func parseEntries(list []string) ([]string, map[string][]string, error) {
+
+	var repos []string
+	tags := make(map[string][]string)
+
+	for _, e := range list {
+		entry := strings.TrimSpace(e)
+		digest := ""
+		if ix := strings.LastIndex(entry, "@"); ix > -1 &&
+			util.IsDigest(entry[ix+1:]) {
+			entry, digest = entry[:ix], entry[ix+1:]
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		repo := strings.Trim(parts[0], "/")
+		if repo == "" {
+			return nil, nil, fmt.Errorf("invalid entry '%s'", e)
+		}
+		if _, ok := tags[repo]; !ok {
+			repos = append(repos, repo)
+			tags[repo] = nil
+		}
+		if len(parts) > 1 || digest != "" {
+			tag := ""
+			if len(parts) > 1 {
+				tag = strings.TrimSpace(parts[1])
+			}
+			tags[repo] = append(tags[repo], util.JoinTag(tag, digest))
+		}
+	}
+
+	return repos, tags, nil
+}
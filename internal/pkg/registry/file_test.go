@@ -0,0 +1,105 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestFileLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"list.txt": `
+# approved images
+library/nginx
+library/redis:7.2
+library/redis:semver: >=7.2.0 <8.0.0
+library/redis@sha256:58f1
+library/redis:7.0@sha256:1d8a
+`,
+		"list.yaml": `
+- library/nginx
+- library/redis:7.2
+`,
+		"map.json": `{"library/nginx": null,
+			"library/redis": ["7.2", "semver: >=7.2.0 <8.0.0"]}`,
+	}
+
+	for name, content := range files {
+		th.AssertNoError(
+			os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	for _, tc := range []struct {
+		file string
+		tags []string
+	}{
+		{"list.txt", []string{"7.2", "semver: >=7.2.0 <8.0.0",
+			"sha256:58f1", "7.0@sha256:1d8a"}},
+		{"list.yaml", []string{"7.2"}},
+		{"map.json", []string{"7.2", "semver: >=7.2.0 <8.0.0"}},
+	} {
+		list, err := NewRepoList("registry.hub.docker.com", false, File,
+			map[string]string{"path": filepath.Join(dir, tc.file)}, nil, nil)
+		th.AssertNoError(err)
+		list.SetMaxItems(1) // must not apply
+		repos, err := list.Get()
+		th.AssertNoError(err)
+		th.AssertEqualSlices(
+			[]string{"library/nginx", "library/redis"}, repos)
+		th.AssertEqual(0, len(list.Tags("library/nginx")))
+		th.AssertEqualSlices(tc.tags, list.Tags("/library/redis"))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/approved" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(files["list.yaml"]))
+		}))
+	defer server.Close()
+
+	list, err := NewRepoList("registry.hub.docker.com", false, File,
+		map[string]string{"url": server.URL + "/approved", "format": "yaml"},
+		nil, nil)
+	th.AssertNoError(err)
+	repos, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"library/nginx", "library/redis"}, repos)
+
+	list, err = NewRepoList("registry.hub.docker.com", false, File,
+		map[string]string{"url": server.URL + "/missing"}, nil, nil)
+	th.AssertNoError(err)
+	_, err = list.Get()
+	th.AssertError(err, "404 Not Found")
+
+	_, err = NewRepoList("registry.hub.docker.com", false, File,
+		map[string]string{}, nil, nil)
+	th.AssertError(err, "requires either a path or a url")
+}
@@ -0,0 +1,208 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+//
+const defaultGARAPI = "https://artifactregistry.googleapis.com"
+
+// newGAR creates a lister using the Google Artifact Registry API, listing the
+// images in the given repository, or in all repositories of the project in
+// the registry's location if repo is empty. The location is derived from the
+// registry, e.g. `europe-west3` for `europe-west3-docker.pkg.dev`, unless set
+// explicitly. Authentication uses the registry password as bearer token, which
+// is where the GCR auth refresher places the access token.
+func newGAR(reg, apiURL, project, location, repo string,
+	creds *auth.Credentials, proxy *util.Proxy) (ListSource, error) {
+
+	server := strings.SplitN(reg, ":", 2)[0]
+
+	if project == "" {
+		return nil, fmt.Errorf("gar lister requires a project")
+	}
+
+	if location == "" {
+		if !strings.HasSuffix(server, "-docker.pkg.dev") {
+			return nil, fmt.Errorf(
+				"cannot derive location from registry '%s'", reg)
+		}
+		location = strings.TrimSuffix(server, "-docker.pkg.dev")
+	}
+
+	if apiURL == "" {
+		apiURL = defaultGARAPI
+	}
+
+	return &gar{
+		apiURL:   strings.TrimSuffix(apiURL, "/"),
+		server:   server,
+		project:  project,
+		location: location,
+		repo:     repo,
+		creds:    creds,
+		proxy:    proxy,
+	}, nil
+}
+
+//
+type gar struct {
+	apiURL   string
+	server   string
+	project  string
+	location string
+	repo     string
+	creds    *auth.Credentials
+	proxy    *util.Proxy
+}
+
+//
+type garRepoList struct {
+	Repositories []struct {
+		Name   string `json:"name"`
+		Format string `json:"format"`
+	} `json:"repositories"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+//
+type garImageList struct {
+	DockerImages []struct {
+		URI string `json:"uri"`
+	} `json:"dockerImages"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+//
+func (g *gar) Retrieve(maxItems int) ([]string, error) {
+
+	if err := g.creds.Refresh(); err != nil {
+		return nil, fmt.Errorf("error refreshing credentials: %v", err)
+	}
+
+	client := g.proxy.Client(false)
+
+	repos := []string{g.repo}
+	if g.repo == "" {
+		var err error
+		if repos, err = g.repositories(client); err != nil {
+			return nil, err
+		}
+	}
+
+	// the API lists each image digest separately, so we need to drop
+	// duplicates
+	var ret []string
+	seen := make(map[string]bool)
+
+	for _, repo := range repos {
+		p := fmt.Sprintf("/repositories/%s/dockerImages", url.PathEscape(repo))
+		for token := ""; ; {
+			var list garImageList
+			if err := g.get(client, p, token, &list); err != nil {
+				return nil, err
+			}
+			for _, i := range list.DockerImages {
+				if p := g.path(i.URI); p != "" && !seen[p] {
+					seen[p] = true
+					ret = append(ret, p)
+				}
+			}
+			if limitReached(ret, maxItems) {
+				return ret, nil
+			}
+			if token = list.NextPageToken; token == "" {
+				break
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// repositories lists the IDs of all Docker repositories in the project's
+// location
+func (g *gar) repositories(client *http.Client) ([]string, error) {
+
+	var ret []string
+
+	for token := ""; ; {
+		var list garRepoList
+		if err := g.get(client, "/repositories", token, &list); err != nil {
+			return nil, err
+		}
+		for _, r := range list.Repositories {
+			if r.Format == "DOCKER" {
+				ret = append(ret, r.Name[strings.LastIndex(r.Name, "/")+1:])
+			}
+		}
+		if token = list.NextPageToken; token == "" {
+			return ret, nil
+		}
+	}
+}
+
+// path returns the repository path of an image URI such as
+// `{server}/{project}/{repository}/{image}@sha256:...`
+func (g *gar) path(uri string) string {
+	uri = strings.SplitN(uri, "@", 2)[0]
+	if !strings.HasPrefix(uri, g.server+"/") {
+		return ""
+	}
+	return uri[len(g.server)+1:]
+}
+
+//
+func (g *gar) Ping() error {
+	var list garRepoList
+	return g.get(g.proxy.Client(false), "/repositories", "", &list)
+}
+
+//
+func (g *gar) get(client *http.Client, p, token string,
+	out interface{}) error {
+
+	q := url.Values{}
+	q.Set("pageSize", strconv.Itoa(apiPageSize))
+	if token != "" {
+		q.Set("pageToken", token)
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf(
+		"%s/v1/projects/%s/locations/%s%s?%s", g.apiURL,
+		url.PathEscape(g.project), url.PathEscape(g.location), p,
+		q.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	if !g.creds.Empty() {
+		req.Header.Set(
+			"Authorization", fmt.Sprintf("Bearer %s", g.creds.Password()))
+	}
+
+	_, err = getJSON(client, req, out)
+	return err
+}
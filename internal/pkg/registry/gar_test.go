@@ -0,0 +1,110 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestGARLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	const host = "europe-west3-docker.pkg.dev"
+	const base = "/v1/projects/acme/locations/europe-west3/repositories"
+
+	images := func(repo string, count int) []map[string]string {
+		var ret []map[string]string
+		for ix := 0; ix < count; ix++ {
+			for _, d := range []string{"sha256:aaa", "sha256:bbb"} {
+				ret = append(ret, map[string]string{"uri": fmt.Sprintf(
+					"%s/acme/%s/app-%d@%s", host, repo, ix, d)})
+			}
+		}
+		return ret
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+
+			if r.Header.Get("Authorization") != "Bearer ya29.token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			enc := json.NewEncoder(w)
+			token := r.URL.Query().Get("pageToken")
+
+			switch r.URL.Path {
+			case base:
+				enc.Encode(map[string]interface{}{
+					"repositories": []map[string]string{
+						{"name": base[4:] + "/docker-a", "format": "DOCKER"},
+						{"name": base[4:] + "/maven", "format": "MAVEN"},
+						{"name": base[4:] + "/docker-b", "format": "DOCKER"},
+					}})
+			case base + "/docker-a/dockerImages":
+				if token == "" {
+					enc.Encode(map[string]interface{}{
+						"dockerImages":  images("docker-a", 3),
+						"nextPageToken": "next"})
+				} else {
+					enc.Encode(map[string]interface{}{
+						"dockerImages": images("docker-a", 4)[6:]})
+				}
+			case base + "/docker-b/dockerImages":
+				enc.Encode(map[string]interface{}{
+					"dockerImages": images("docker-b", 1)})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer server.Close()
+
+	creds, err := auth.NewCredentialsFromBasic(
+		"oauth2accesstoken", "ya29.token")
+	th.AssertNoError(err)
+
+	list, err := NewRepoList(host, false, GAR, map[string]string{
+		"url": server.URL, "project": "acme", "repository": "docker-a"},
+		creds, nil)
+	th.AssertNoError(err)
+	res, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"acme/docker-a/app-0", "acme/docker-a/app-1",
+		"acme/docker-a/app-2", "acme/docker-a/app-3"}, res)
+
+	list, err = NewRepoList(host, false, GAR, map[string]string{
+		"url": server.URL, "project": "acme"}, creds, nil)
+	th.AssertNoError(err)
+	res, err = list.Get()
+	th.AssertNoError(err)
+	th.AssertEqual(5, len(res))
+	th.AssertEqual("acme/docker-b/app-0", res[4])
+
+	_, err = NewRepoList("gcr.io", false, GAR,
+		map[string]string{"project": "acme"}, creds, nil)
+	th.AssertError(err, "cannot derive location")
+}
@@ -0,0 +1,159 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+//
+const defaultGitHubAPI = "https://api.github.com"
+
+// newGHCR creates a lister using the GitHub Packages API, listing the container
+// packages of either an organization or a user, optionally only those with the
+// given visibility. For authentication, token is used if set, otherwise the
+// registry password.
+func newGHCR(apiURL, org, user, visibility, token string,
+	creds *auth.Credentials, proxy *util.Proxy) (ListSource, error) {
+
+	if (org == "") == (user == "") {
+		return nil, fmt.Errorf("ghcr lister requires either an org or a user")
+	}
+
+	switch visibility {
+	case "", "public", "private", "internal":
+	default:
+		return nil, fmt.Errorf("invalid ghcr visibility '%s'", visibility)
+	}
+
+	if apiURL == "" {
+		apiURL = defaultGitHubAPI
+	}
+
+	ret := &ghcr{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		visibility: visibility,
+		token:      token,
+		creds:      creds,
+		proxy:      proxy,
+	}
+
+	if org != "" {
+		ret.owner = org
+		ret.path = fmt.Sprintf("/orgs/%s/packages", url.PathEscape(org))
+	} else {
+		ret.owner = user
+		ret.path = fmt.Sprintf("/users/%s/packages", url.PathEscape(user))
+	}
+
+	return ret, nil
+}
+
+//
+type ghcr struct {
+	apiURL     string
+	path       string
+	owner      string
+	visibility string
+	token      string
+	creds      *auth.Credentials
+	proxy      *util.Proxy
+}
+
+//
+type ghcrPackage struct {
+	Name string `json:"name"`
+}
+
+//
+func (g *ghcr) Retrieve(maxItems int) ([]string, error) {
+
+	if err := g.creds.Refresh(); err != nil {
+		return nil, fmt.Errorf("error refreshing credentials: %v", err)
+	}
+
+	var ret []string
+	client := g.proxy.Client(false)
+	next := g.pageURL(apiPageSize)
+
+	// repository paths in ghcr.io are always lower case
+	owner := strings.ToLower(g.owner)
+
+	for next != "" {
+		var packages []ghcrPackage
+		header, err := g.get(client, next, &packages)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range packages {
+			ret = append(ret, fmt.Sprintf(
+				"%s/%s", owner, strings.ToLower(p.Name)))
+		}
+		if limitReached(ret, maxItems) {
+			break
+		}
+		next = nextLink(header)
+	}
+
+	return ret, nil
+}
+
+//
+func (g *ghcr) Ping() error {
+	var packages []ghcrPackage
+	_, err := g.get(g.proxy.Client(false), g.pageURL(1), &packages)
+	return err
+}
+
+//
+func (g *ghcr) pageURL(size int) string {
+	q := url.Values{}
+	q.Set("package_type", "container")
+	q.Set("per_page", strconv.Itoa(size))
+	if g.visibility != "" {
+		q.Set("visibility", g.visibility)
+	}
+	return fmt.Sprintf("%s%s?%s", g.apiURL, g.path, q.Encode())
+}
+
+//
+func (g *ghcr) get(client *http.Client, u string,
+	packages *[]ghcrPackage) (http.Header, error) {
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	token := g.token
+	if token == "" && !g.creds.Empty() {
+		token = g.creds.Password()
+	}
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	return getJSON(client, req, packages)
+}
@@ -0,0 +1,107 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestGHCRLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var packages []map[string]string
+	for ix := 0; ix < 130; ix++ {
+		packages = append(packages, map[string]string{
+			"name": fmt.Sprintf("App-%03d", ix), "visibility": "public"})
+	}
+	packages[7]["visibility"] = "private"
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+
+			if r.Header.Get("Authorization") != "Bearer ghp_secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Path != "/orgs/Acme/packages" ||
+				r.URL.Query().Get("package_type") != "container" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			var matches []map[string]string
+			v := r.URL.Query().Get("visibility")
+			for _, p := range packages {
+				if v == "" || p["visibility"] == v {
+					matches = append(matches, p)
+				}
+			}
+
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page == 0 {
+				page = 1
+			}
+			size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+			start, end := (page-1)*size, page*size
+			if end < len(matches) {
+				q := r.URL.Query()
+				q.Set("page", strconv.Itoa(page+1))
+				w.Header().Set("Link", fmt.Sprintf(
+					`<%s%s?%s>; rel="next", <%s/last>; rel="last"`,
+					server.URL, r.URL.Path, q.Encode(), server.URL))
+			} else {
+				end = len(matches)
+			}
+			json.NewEncoder(w).Encode(matches[start:end])
+		}))
+	defer server.Close()
+
+	creds, err := auth.NewCredentialsFromBasic("someone", "ghp_secret")
+	th.AssertNoError(err)
+
+	list, err := NewRepoList("ghcr.io", false, GHCR,
+		map[string]string{"url": server.URL, "org": "Acme"}, creds, nil)
+	th.AssertNoError(err)
+	list.SetMaxItems(-1)
+	res, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqual(130, len(res))
+	th.AssertEqual("acme/app-129", res[129])
+
+	list, err = NewRepoList("ghcr.io", false, GHCR, map[string]string{
+		"url": server.URL, "org": "Acme", "visibility": "private"},
+		creds, nil)
+	th.AssertNoError(err)
+	res, err = list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"acme/app-007"}, res)
+
+	_, err = NewRepoList("ghcr.io", false, GHCR,
+		map[string]string{"org": "acme", "visibility": "secret"}, creds, nil)
+	th.AssertError(err, "invalid ghcr visibility 'secret'")
+}
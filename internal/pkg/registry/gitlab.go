@@ -0,0 +1,152 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// newGitLab creates a lister using the GitLab API, listing the registry
+// repositories of either a group or a project. The API is expected at the
+// registry's domain without a leading `registry.`, unless apiURL is set. For
+// authentication, token is used if set, otherwise the registry password. With
+// tokenType `job`, the token is sent as a CI job token.
+func newGitLab(reg, apiURL, group, project, token, tokenType string,
+	insecure bool, creds *auth.Credentials, proxy *util.Proxy) (
+	ListSource, error) {
+
+	if (group == "") == (project == "") {
+		return nil, fmt.Errorf(
+			"gitlab lister requires either a group or a project")
+	}
+
+	if apiURL == "" {
+		server := strings.SplitN(reg, ":", 2)[0]
+		apiURL = fmt.Sprintf("https://%s", strings.TrimPrefix(
+			server, "registry."))
+	}
+
+	ret := &gitlab{
+		apiURL:   strings.TrimSuffix(apiURL, "/"),
+		token:    token,
+		header:   "PRIVATE-TOKEN",
+		insecure: insecure,
+		creds:    creds,
+		proxy:    proxy,
+	}
+
+	switch tokenType {
+	case "", "private":
+	case "job":
+		ret.header = "JOB-TOKEN"
+	default:
+		return nil, fmt.Errorf("invalid gitlab token type '%s'", tokenType)
+	}
+
+	if group != "" {
+		ret.path = fmt.Sprintf("/api/v4/groups/%s/registry/repositories",
+			url.PathEscape(group))
+	} else {
+		ret.path = fmt.Sprintf("/api/v4/projects/%s/registry/repositories",
+			url.PathEscape(project))
+	}
+
+	return ret, nil
+}
+
+//
+type gitlab struct {
+	apiURL   string
+	path     string
+	token    string
+	header   string
+	insecure bool
+	creds    *auth.Credentials
+	proxy    *util.Proxy
+}
+
+//
+type gitlabRepo struct {
+	Path string `json:"path"`
+}
+
+//
+func (g *gitlab) Retrieve(maxItems int) ([]string, error) {
+
+	if err := g.creds.Refresh(); err != nil {
+		return nil, fmt.Errorf("error refreshing credentials: %v", err)
+	}
+
+	var ret []string
+	client := g.proxy.Client(g.insecure)
+
+	for page := "1"; page != ""; {
+		var repos []gitlabRepo
+		header, err := g.get(client, page, apiPageSize, &repos)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			ret = append(ret, r.Path)
+		}
+		if limitReached(ret, maxItems) {
+			break
+		}
+		page = header.Get("X-Next-Page")
+	}
+
+	return ret, nil
+}
+
+//
+func (g *gitlab) Ping() error {
+	var repos []gitlabRepo
+	_, err := g.get(g.proxy.Client(g.insecure), "1", 1, &repos)
+	return err
+}
+
+//
+func (g *gitlab) get(client *http.Client, page string, size int,
+	repos *[]gitlabRepo) (http.Header, error) {
+
+	q := url.Values{}
+	q.Set("page", page)
+	q.Set("per_page", strconv.Itoa(size))
+
+	req, err := http.NewRequest(
+		"GET", fmt.Sprintf("%s%s?%s", g.apiURL, g.path, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	token := g.token
+	if token == "" && !g.creds.Empty() {
+		token = g.creds.Password()
+	}
+	if token != "" {
+		req.Header.Set(g.header, token)
+	}
+
+	return getJSON(client, req, repos)
+}
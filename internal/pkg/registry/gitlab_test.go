@@ -0,0 +1,101 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestGitLabLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var repos []map[string]string
+	for ix := 0; ix < 120; ix++ {
+		repos = append(repos, map[string]string{
+			"path": fmt.Sprintf("acme/backend/app-%03d", ix)})
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+
+			if r.Header.Get("PRIVATE-TOKEN") != "glpat-secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			switch r.URL.EscapedPath() {
+			case "/api/v4/groups/acme%2Fbackend/registry/repositories":
+			case "/api/v4/projects/42/registry/repositories":
+				json.NewEncoder(w).Encode(repos[:1])
+				return
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+			start, end := (page-1)*size, page*size
+			if end < len(repos) {
+				w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+			} else {
+				end = len(repos)
+			}
+			json.NewEncoder(w).Encode(repos[start:end])
+		}))
+	defer server.Close()
+
+	creds, err := auth.NewCredentialsFromBasic("ci", "glpat-secret")
+	th.AssertNoError(err)
+
+	list, err := NewRepoList("registry.gitlab.local", true, GitLab,
+		map[string]string{"url": server.URL, "group": "acme/backend"},
+		creds, nil)
+	th.AssertNoError(err)
+	list.SetMaxItems(-1)
+	res, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqual(120, len(res))
+	th.AssertEqual("acme/backend/app-119", res[119])
+
+	list, err = NewRepoList("registry.gitlab.local", true, GitLab,
+		map[string]string{"url": server.URL, "project": "42",
+			"token": "glpat-secret"}, &auth.Credentials{}, nil)
+	th.AssertNoError(err)
+	res, err = list.Get()
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"acme/backend/app-000"}, res)
+
+	_, err = NewRepoList("registry.gitlab.local", true, GitLab,
+		map[string]string{}, creds, nil)
+	th.AssertError(err, "requires either a group or a project")
+
+	g, err := newGitLab("registry.gitlab.com:443", "", "acme", "", "", "",
+		false, creds, nil)
+	th.AssertNoError(err)
+	th.AssertEqual("https://gitlab.com", g.(*gitlab).apiURL)
+}
@@ -0,0 +1,124 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// newHarbor creates a lister using the Harbor API, since Harbor restricts the
+// catalog API to admins. If project is set, only repositories in that project
+// are listed. If search is set, only repositories with names containing it are
+// listed, using Harbor's fuzzy match.
+func newHarbor(reg, project, search string, insecure bool,
+	creds *auth.Credentials, proxy *util.Proxy) ListSource {
+	return &harbor{
+		registry: reg,
+		project:  project,
+		search:   search,
+		insecure: insecure,
+		creds:    creds,
+		proxy:    proxy,
+	}
+}
+
+//
+type harbor struct {
+	registry string
+	project  string
+	search   string
+	insecure bool
+	creds    *auth.Credentials
+	proxy    *util.Proxy
+}
+
+//
+type harborRepo struct {
+	Name string `json:"name"`
+}
+
+//
+func (h *harbor) Retrieve(maxItems int) ([]string, error) {
+
+	if err := h.creds.Refresh(); err != nil {
+		return nil, fmt.Errorf("error refreshing credentials: %v", err)
+	}
+
+	var ret []string
+	client := h.proxy.Client(h.insecure)
+
+	for page := 1; ; page++ {
+		var repos []harborRepo
+		if err := h.get(client, page, apiPageSize, &repos); err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			ret = append(ret, r.Name)
+		}
+		if len(repos) < apiPageSize || limitReached(ret, maxItems) {
+			return ret, nil
+		}
+	}
+}
+
+//
+func (h *harbor) Ping() error {
+	var repos []harborRepo
+	return h.get(h.proxy.Client(h.insecure), 1, 1, &repos)
+}
+
+//
+func (h *harbor) get(client *http.Client, page, size int,
+	repos *[]harborRepo) error {
+
+	path := "/api/v2.0/repositories"
+	if h.project != "" {
+		path = fmt.Sprintf(
+			"/api/v2.0/projects/%s/repositories", url.PathEscape(h.project))
+	}
+
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("page_size", strconv.Itoa(size))
+	if h.search != "" {
+		q.Set("q", fmt.Sprintf("name=~%s", h.search))
+	}
+
+	u := url.URL{
+		Scheme:   "https",
+		Host:     h.registry,
+		Path:     path,
+		RawQuery: q.Encode(),
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if !h.creds.Empty() {
+		req.SetBasicAuth(h.creds.Username(), h.creds.Password())
+	}
+
+	_, err = getJSON(client, req, repos)
+	return err
+}
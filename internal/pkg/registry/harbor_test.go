@@ -0,0 +1,109 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestHarborLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var repos []string
+	for ix := 0; ix < 150; ix++ {
+		repos = append(repos, fmt.Sprintf("acme/app-%03d", ix))
+	}
+	repos = append(repos, "other/tool")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+
+			if u, p, ok := r.BasicAuth(); !ok || u != "robot" || p != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			prefix := ""
+			switch r.URL.Path {
+			case "/api/v2.0/repositories":
+			case "/api/v2.0/projects/acme/repositories":
+				prefix = "acme/"
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			search := strings.TrimPrefix(r.URL.Query().Get("q"), "name=~")
+			var matches []map[string]string
+			for _, r := range repos {
+				if strings.HasPrefix(r, prefix) && strings.Contains(r, search) {
+					matches = append(matches, map[string]string{"name": r})
+				}
+			}
+
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+			start, end := (page-1)*size, page*size
+			if start > len(matches) {
+				start = len(matches)
+			}
+			if end > len(matches) {
+				end = len(matches)
+			}
+			json.NewEncoder(w).Encode(matches[start:end])
+		}))
+	defer server.Close()
+
+	reg := strings.TrimPrefix(server.URL, "https://")
+	creds, err := auth.NewCredentialsFromBasic("robot", "secret")
+	th.AssertNoError(err)
+
+	for _, tc := range []struct {
+		config   map[string]string
+		expected int
+	}{
+		{map[string]string{}, 151},
+		{map[string]string{"project": "acme"}, 150},
+		{map[string]string{"project": "acme", "search": "app-14"}, 10},
+		{map[string]string{"search": "tool"}, 1},
+	} {
+		list, err := NewRepoList(reg, true, Harbor, tc.config, creds, nil)
+		th.AssertNoError(err)
+		list.SetMaxItems(-1)
+		res, err := list.Get()
+		th.AssertNoError(err)
+		th.AssertEqual(tc.expected, len(res))
+	}
+
+	creds, err = auth.NewCredentialsFromBasic("robot", "wrong")
+	th.AssertNoError(err)
+	list, err := NewRepoList(reg, true, Harbor, nil, creds, nil)
+	th.AssertNoError(err)
+	_, err = list.Get()
+	th.AssertError(err, "401 Unauthorized")
+}
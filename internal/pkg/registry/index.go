@@ -18,20 +18,26 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/docker/docker/api/types/filters"
 	types "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/registry"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
 //-
-func newIndex(reg, filter string, insecure bool, creds *auth.Credentials) ListSource {
+func newIndex(reg, filter string, insecure bool, creds *auth.Credentials,
+	proxy *util.Proxy) ListSource {
 
-	ret := &index{filter: filter}
+	ret := &index{filter: filter, insecure: insecure, proxy: proxy}
 
 	if !isDockerHub(reg) {
 		ret.filter = fmt.Sprintf("%s/%s", reg, filter)
@@ -57,14 +63,23 @@ func newIndex(reg, filter string, insecure bool, creds *auth.Credentials) ListSo
 
 //-
 type index struct {
-	opts   *registry.ServiceOptions
-	auth   *types.AuthConfig
-	filter string
+	opts     *registry.ServiceOptions
+	auth     *types.AuthConfig
+	filter   string
+	insecure bool
+	proxy    *util.Proxy
 }
 
 //-
 func (i *index) Retrieve(maxItems int) ([]string, error) {
 
+	// The Docker registry service always picks up proxy settings from the
+	// environment, so when a proxy is configured for the location, we need
+	// to run the search ourselves.
+	if i.proxy.IsSet() {
+		return i.search(maxItems)
+	}
+
 	svc, err := registry.NewService(*i.opts)
 	if err != nil {
 		return nil, err
@@ -85,6 +100,61 @@ func (i *index) Retrieve(maxItems int) ([]string, error) {
 	return ret, nil
 }
 
+//-
+func (i *index) search(maxItems int) ([]string, error) {
+
+	server, term := i.auth.ServerAddress, i.filter
+	if isDockerHub(server) {
+		server = "index.docker.io"
+	} else {
+		term = strings.TrimPrefix(term, server+"/")
+	}
+
+	if maxItems <= 0 || maxItems > 100 {
+		maxItems = 100 // limit imposed by search API
+	}
+
+	scheme := "https"
+	if i.insecure {
+		scheme = "http"
+	}
+
+	u := fmt.Sprintf("%s://%s/v1/search?q=%s&n=%d",
+		scheme, server, url.QueryEscape(term), maxItems)
+	log.WithField("url", u).Debug("searching index via proxy")
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "dregsy")
+	if i.auth.Username != "" {
+		req.SetBasicAuth(i.auth.Username, i.auth.Password)
+	}
+
+	resp, err := i.proxy.Client(i.insecure).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("index search failed: %s", resp.Status)
+	}
+
+	var res types.SearchResults
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("error decoding search results: %v", err)
+	}
+
+	ret := make([]string, 0, len(res.Results))
+	for _, r := range res.Results {
+		ret = append(ret, r.Name)
+	}
+
+	return ret, nil
+}
+
 //-
 func (i *index) Ping() error {
 	svc, err := registry.NewService(*i.opts)
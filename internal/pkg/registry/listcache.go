@@ -0,0 +1,135 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// repo lists shared among tasks
+var repoLists = &repoListCache{lists: make(map[string]*RepoList)}
+
+//
+type repoListCache struct {
+	mutex  sync.Mutex
+	lists  map[string]*RepoList
+	hits   int
+	misses int
+}
+
+// SharedRepoList returns a repo list for the given settings, as NewRepoList
+// does, and limited to prefix as set by RepoList.SetPrefix. Tasks asking for a
+// repo list with identical settings & credentials get the same instance, so
+// that the repositories are only listed once.
+func SharedRepoList(registry string, insecure bool, typ ListSourceType,
+	config map[string]string, creds *auth.Credentials, proxy *util.Proxy,
+	prefix string) (*RepoList, error) {
+
+	key := repoListKey(registry, insecure, typ, config, creds, proxy, prefix)
+
+	repoLists.mutex.Lock()
+	defer repoLists.mutex.Unlock()
+
+	if l, ok := repoLists.lists[key]; ok {
+		repoLists.hits++
+		log.WithFields(log.Fields{
+			"registry": registry,
+			"hits":     repoLists.hits,
+			"misses":   repoLists.misses}).Debug("re-using shared repo list")
+		return l, nil
+	}
+
+	l, err := NewRepoList(registry, insecure, typ, config, creds, proxy)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" {
+		l.SetPrefix(prefix)
+	}
+
+	repoLists.misses++
+	log.WithFields(log.Fields{
+		"registry": registry,
+		"hits":     repoLists.hits,
+		"misses":   repoLists.misses}).Debug("created shared repo list")
+
+	repoLists.lists[key] = l
+	return l, nil
+}
+
+// ResetSharedRepoLists drops all shared repo lists and resets the statistics,
+// e.g. when loading a new config.
+func ResetSharedRepoLists() {
+	repoLists.mutex.Lock()
+	defer repoLists.mutex.Unlock()
+	repoLists.lists = make(map[string]*RepoList)
+	repoLists.hits = 0
+	repoLists.misses = 0
+}
+
+// SharedRepoListStats returns the number of times a shared repo list could be
+// re-used, and the number of times a new one had to be created.
+func SharedRepoListStats() (hits, misses int) {
+	repoLists.mutex.Lock()
+	defer repoLists.mutex.Unlock()
+	return repoLists.hits, repoLists.misses
+}
+
+// repoListKey identifies a repo list by all settings that affect listing. The
+// credentials are included via a hash, to not keep the password in the clear.
+func repoListKey(registry string, insecure bool, typ ListSourceType,
+	config map[string]string, creds *auth.Credentials, proxy *util.Proxy,
+	prefix string) string {
+
+	if typ == "" {
+		typ = Catalog
+	}
+
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		if k != "type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s|%t|%s|%s", registry, insecure, typ, prefix)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "|%s=%s", k, config[k])
+	}
+
+	if !creds.Empty() {
+		fmt.Fprintf(&b, "|%s:%x", creds.Username(),
+			sha256.Sum256([]byte(creds.Password())))
+	}
+
+	if proxy.IsSet() {
+		fmt.Fprintf(&b, "|%s|%s", proxy.URL, proxy.NoProxy)
+	}
+
+	return b.String()
+}
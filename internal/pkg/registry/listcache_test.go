@@ -0,0 +1,92 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestSharedRepoList(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var served int32
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&served, 1)
+			w.Write([]byte("library/nginx\nlibrary/redis\n"))
+		}))
+	defer server.Close()
+
+	ResetSharedRepoLists()
+
+	shared := func(config map[string]string, creds *auth.Credentials,
+		prefix string) *RepoList {
+		l, err := SharedRepoList("registry.hub.docker.com", false, File,
+			config, creds, nil, prefix)
+		th.AssertNoError(err)
+		return l
+	}
+
+	config := map[string]string{"url": server.URL, "format": "text"}
+	list := shared(config, nil, "")
+	th.AssertTrue(list == shared(
+		map[string]string{"format": "text", "url": server.URL}, nil, ""))
+	th.AssertTrue(list != shared(config, nil, "library/"))
+	th.AssertTrue(list != shared(
+		map[string]string{"url": server.URL + "/other"}, nil, ""))
+	creds, err := auth.NewCredentialsFromBasic("user", "secret")
+	th.AssertNoError(err)
+	th.AssertTrue(list != shared(config, creds, ""))
+	creds, err = auth.NewCredentialsFromBasic("user", "other")
+	th.AssertNoError(err)
+	th.AssertTrue(list != shared(config, creds, ""))
+
+	h, m := SharedRepoListStats()
+	th.AssertEqual(1, h)
+	th.AssertEqual(5, m)
+
+	list.SetCacheDuration(defaultListerCacheDuration)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repos, err := list.Get()
+			th.AssertNoError(err)
+			th.AssertEqualSlices(
+				[]string{"library/nginx", "library/redis"}, repos)
+		}()
+	}
+	wg.Wait()
+	th.AssertEqual(int32(1), atomic.LoadInt32(&served))
+
+	ResetSharedRepoLists()
+	h, m = SharedRepoListStats()
+	th.AssertEqual(0, h)
+	th.AssertEqual(0, m)
+	th.AssertTrue(list != shared(config, nil, ""))
+}
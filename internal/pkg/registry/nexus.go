@@ -0,0 +1,130 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// newNexus creates a lister using the search API of Sonatype Nexus, listing
+// the Docker images in the given repository, or in all Docker repositories if
+// repo is empty. The API is expected at the registry server, unless apiURL is
+// set. The registry credentials are used for authentication.
+func newNexus(reg, apiURL, repo string, insecure bool,
+	creds *auth.Credentials, proxy *util.Proxy) ListSource {
+
+	if apiURL == "" {
+		// Nexus serves Docker repositories on dedicated ports, while the API
+		// is served on the default port
+		server := strings.SplitN(reg, ":", 2)[0]
+		apiURL = fmt.Sprintf("https://%s", server)
+	}
+
+	return &nexus{
+		apiURL:   strings.TrimSuffix(apiURL, "/"),
+		repo:     repo,
+		insecure: insecure,
+		creds:    creds,
+		proxy:    proxy,
+	}
+}
+
+//
+type nexus struct {
+	apiURL   string
+	repo     string
+	insecure bool
+	creds    *auth.Credentials
+	proxy    *util.Proxy
+}
+
+//
+type nexusSearchResult struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	ContinuationToken string `json:"continuationToken"`
+}
+
+//
+func (n *nexus) Retrieve(maxItems int) ([]string, error) {
+
+	if err := n.creds.Refresh(); err != nil {
+		return nil, fmt.Errorf("error refreshing credentials: %v", err)
+	}
+
+	// search yields one item per image version, so we need to drop duplicates
+	var ret []string
+	seen := make(map[string]bool)
+	client := n.proxy.Client(n.insecure)
+
+	for token := ""; ; {
+		var res nexusSearchResult
+		if err := n.get(client, token, &res); err != nil {
+			return nil, err
+		}
+		for _, i := range res.Items {
+			if !seen[i.Name] {
+				seen[i.Name] = true
+				ret = append(ret, i.Name)
+			}
+		}
+		if res.ContinuationToken == "" || limitReached(ret, maxItems) {
+			return ret, nil
+		}
+		token = res.ContinuationToken
+	}
+}
+
+//
+func (n *nexus) Ping() error {
+	var res nexusSearchResult
+	return n.get(n.proxy.Client(n.insecure), "", &res)
+}
+
+//
+func (n *nexus) get(client *http.Client, token string,
+	res *nexusSearchResult) error {
+
+	q := url.Values{}
+	q.Set("format", "docker")
+	if n.repo != "" {
+		q.Set("repository", n.repo)
+	}
+	if token != "" {
+		q.Set("continuationToken", token)
+	}
+
+	req, err := http.NewRequest("GET",
+		fmt.Sprintf("%s/service/rest/v1/search?%s", n.apiURL, q.Encode()),
+		nil)
+	if err != nil {
+		return err
+	}
+	if !n.creds.Empty() {
+		req.SetBasicAuth(n.creds.Username(), n.creds.Password())
+	}
+
+	_, err = getJSON(client, req, res)
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestNexusLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	// one item per image version
+	var items []map[string]string
+	for ix := 0; ix < 40; ix++ {
+		for _, v := range []string{"1.0", "2.0"} {
+			items = append(items, map[string]string{
+				"name": fmt.Sprintf("acme/app-%02d", ix), "version": v})
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+
+			q := r.URL.Query()
+			if r.URL.Path != "/service/rest/v1/search" ||
+				q.Get("format") != "docker" ||
+				q.Get("repository") != "docker-hosted" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			start, _ := strconv.Atoi(q.Get("continuationToken"))
+			res := map[string]interface{}{}
+			end := start + 50
+			if end < len(items) {
+				res["continuationToken"] = strconv.Itoa(end)
+			} else {
+				end = len(items)
+			}
+			res["items"] = items[start:end]
+			json.NewEncoder(w).Encode(res)
+		}))
+	defer server.Close()
+
+	list, err := NewRepoList("nexus.acme.com:8443", false, Nexus,
+		map[string]string{"url": server.URL, "repository": "docker-hosted"},
+		&auth.Credentials{}, nil)
+	th.AssertNoError(err)
+	list.SetMaxItems(-1)
+	res, err := list.Get()
+	th.AssertNoError(err)
+	th.AssertEqual(40, len(res))
+	th.AssertEqual("acme/app-39", res[39])
+}
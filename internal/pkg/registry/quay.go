@@ -0,0 +1,148 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// newQuay creates a lister using the Quay API, listing the repositories of a
+// namespace, optionally only those with the given visibility. The API is
+// expected at the registry, unless apiURL is set. For authentication, token is
+// used if set, otherwise the registry password.
+func newQuay(reg, apiURL, namespace, visibility, token string, insecure bool,
+	creds *auth.Credentials, proxy *util.Proxy) (ListSource, error) {
+
+	if namespace == "" {
+		return nil, fmt.Errorf("quay lister requires a namespace")
+	}
+
+	switch visibility {
+	case "", "public", "private":
+	default:
+		return nil, fmt.Errorf("invalid quay visibility '%s'", visibility)
+	}
+
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://%s", reg)
+	}
+
+	return &quay{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		namespace:  namespace,
+		visibility: visibility,
+		token:      token,
+		insecure:   insecure,
+		creds:      creds,
+		proxy:      proxy,
+	}, nil
+}
+
+//
+type quay struct {
+	apiURL     string
+	namespace  string
+	visibility string
+	token      string
+	insecure   bool
+	creds      *auth.Credentials
+	proxy      *util.Proxy
+}
+
+//
+type quayRepoList struct {
+	Repositories []quayRepo `json:"repositories"`
+	NextPage     string     `json:"next_page"`
+}
+
+//
+type quayRepo struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	IsPublic  bool   `json:"is_public"`
+}
+
+//
+func (q *quay) Retrieve(maxItems int) ([]string, error) {
+
+	if err := q.creds.Refresh(); err != nil {
+		return nil, fmt.Errorf("error refreshing credentials: %v", err)
+	}
+
+	var ret []string
+	client := q.proxy.Client(q.insecure)
+
+	for next := ""; ; {
+		var list quayRepoList
+		if err := q.get(client, next, &list); err != nil {
+			return nil, err
+		}
+		for _, r := range list.Repositories {
+			if q.visibility == "private" && r.IsPublic ||
+				q.visibility == "public" && !r.IsPublic {
+				continue
+			}
+			ret = append(ret, fmt.Sprintf("%s/%s", r.Namespace, r.Name))
+		}
+		if list.NextPage == "" || limitReached(ret, maxItems) {
+			return ret, nil
+		}
+		next = list.NextPage
+	}
+}
+
+//
+func (q *quay) Ping() error {
+	var list quayRepoList
+	return q.get(q.proxy.Client(q.insecure), "", &list)
+}
+
+//
+func (q *quay) get(client *http.Client, next string,
+	list *quayRepoList) error {
+
+	v := url.Values{}
+	v.Set("namespace", q.namespace)
+	// without this, only repositories the user has access to are listed
+	v.Set("public", fmt.Sprintf("%t", q.visibility != "private"))
+	if next != "" {
+		v.Set("next_page", next)
+	}
+
+	req, err := http.NewRequest("GET",
+		fmt.Sprintf("%s/api/v1/repository?%s", q.apiURL, v.Encode()), nil)
+	if err != nil {
+		return err
+	}
+
+	token := q.token
+	if token == "" && !q.creds.Empty() {
+		token = q.creds.Password()
+	}
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	_, err = getJSON(client, req, list)
+	return err
+}
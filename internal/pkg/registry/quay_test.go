@@ -0,0 +1,107 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestQuayLister(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var repos []*quayRepo
+	for ix := 0; ix < 75; ix++ {
+		repos = append(repos, &quayRepo{Namespace: "acme",
+			Name: fmt.Sprintf("app-%02d", ix), IsPublic: ix%5 != 0})
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+
+			q := r.URL.Query()
+			if r.URL.Path != "/api/v1/repository" ||
+				q.Get("namespace") != "acme" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			// private repos only with token
+			authorized := r.Header.Get("Authorization") == "Bearer secret"
+			var matches []*quayRepo
+			for _, r := range repos {
+				if r.IsPublic && q.Get("public") == "true" ||
+					!r.IsPublic && authorized {
+					matches = append(matches, r)
+				}
+			}
+
+			start := 0
+			if next := q.Get("next_page"); next != "" {
+				start, _ = strconv.Atoi(strings.TrimPrefix(next, "token-"))
+			}
+			list := &quayRepoList{}
+			end := start + 20
+			if end < len(matches) {
+				list.NextPage = fmt.Sprintf("token-%d", end)
+			} else {
+				end = len(matches)
+			}
+			for _, r := range matches[start:end] {
+				list.Repositories = append(list.Repositories, *r)
+			}
+			json.NewEncoder(w).Encode(list)
+		}))
+	defer server.Close()
+
+	reg := strings.TrimPrefix(server.URL, "https://")
+	creds, err := auth.NewCredentialsFromBasic("$oauthtoken", "secret")
+	th.AssertNoError(err)
+
+	for _, tc := range []struct {
+		visibility string
+		creds      *auth.Credentials
+		expected   int
+	}{
+		{"", creds, 75},
+		{"", &auth.Credentials{}, 60},
+		{"public", creds, 60},
+		{"private", creds, 15},
+	} {
+		list, err := NewRepoList(reg, true, Quay, map[string]string{
+			"namespace": "acme", "visibility": tc.visibility},
+			tc.creds, nil)
+		th.AssertNoError(err)
+		list.SetMaxItems(-1)
+		res, err := list.Get()
+		th.AssertNoError(err)
+		th.AssertEqual(tc.expected, len(res))
+	}
+
+	_, err = NewRepoList(reg, true, Quay, map[string]string{}, creds, nil)
+	th.AssertError(err, "quay lister requires a namespace")
+}
@@ -0,0 +1,610 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	gocrauthn "github.com/google/go-containerregistry/pkg/authn"
+	gocrname "github.com/google/go-containerregistry/pkg/name"
+	gocrv1 "github.com/google/go-containerregistry/pkg/v1"
+	gocrmutate "github.com/google/go-containerregistry/pkg/v1/mutate"
+	gocrremote "github.com/google/go-containerregistry/pkg/v1/remote"
+	gocrtransport "github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// Remote gives direct access to images in a registry via the registry API,
+// for things that the relays cannot do for us.
+type Remote struct {
+	insecure  bool
+	transport *http.Transport
+	opts      []gocrremote.Option
+}
+
+// NewRemote creates a Remote, with auth being the base64 encoded JSON auth as
+// used in sync options.
+func NewRemote(auth string, skipTLSVerify bool, proxy *util.Proxy) *Remote {
+
+	var authn gocrauthn.Authenticator = gocrauthn.Anonymous
+	if creds := util.DecodeJSONAuth(auth); creds != "" {
+		user, pass, _ := strings.Cut(creds, ":")
+		authn = &gocrauthn.Basic{Username: user, Password: pass}
+	}
+
+	transport := proxy.Transport(skipTLSVerify)
+
+	return &Remote{
+		insecure:  skipTLSVerify,
+		transport: transport,
+		opts: []gocrremote.Option{
+			gocrremote.WithAuth(authn),
+			gocrremote.WithTransport(transport),
+			gocrremote.WithUserAgent("dregsy"),
+		},
+	}
+}
+
+// UseCertDir sets up TLS with the certificates in dir, following the layout of
+// Docker's & Skopeo's certs.d folders: `*.crt` files are trusted as CAs in
+// addition to the system's, and `*.cert` & `*.key` pairs are used as client
+// certificates. A missing dir is ignored.
+func (r *Remote) UseCertDir(dir string) error {
+
+	if dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot read certificate dir '%s': %v", dir, err)
+	}
+
+	if r.transport.TLSClientConfig == nil {
+		r.transport.TLSClientConfig = &tls.Config{}
+	}
+	conf := r.transport.TLSClientConfig
+
+	for _, e := range entries {
+
+		file := filepath.Join(dir, e.Name())
+
+		switch filepath.Ext(e.Name()) {
+
+		case ".crt":
+			pem, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("cannot read CA certificate: %v", err)
+			}
+			if conf.RootCAs == nil {
+				if conf.RootCAs, err = x509.SystemCertPool(); err != nil {
+					conf.RootCAs = x509.NewCertPool()
+				}
+			}
+			if !conf.RootCAs.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("no valid CA certificate in '%s'", file)
+			}
+
+		case ".cert":
+			key := strings.TrimSuffix(file, ".cert") + ".key"
+			cert, err := tls.LoadX509KeyPair(file, key)
+			if err != nil {
+				return fmt.Errorf("cannot load client certificate: %v", err)
+			}
+			conf.Certificates = append(conf.Certificates, cert)
+		}
+	}
+
+	return nil
+}
+
+//
+func (r *Remote) nameOpts() []gocrname.Option {
+	if r.insecure {
+		return []gocrname.Option{gocrname.Insecure}
+	}
+	return nil
+}
+
+//
+func (r *Remote) ref(ref string) (gocrname.Reference, error) {
+	ret, err := gocrname.ParseReference(ref, r.nameOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference '%s': %v", ref, err)
+	}
+	return ret, nil
+}
+
+// ListTags lists all tags of repository repo.
+func (r *Remote) ListTags(repo string) ([]string, error) {
+	rp, err := gocrname.NewRepository(repo, r.nameOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository '%s': %v", repo, err)
+	}
+	return gocrremote.List(rp, r.opts...)
+}
+
+// Digest returns the manifest digest for ref.
+func (r *Remote) Digest(ref string) (string, error) {
+	rf, err := r.ref(ref)
+	if err != nil {
+		return "", err
+	}
+	desc, err := gocrremote.Head(rf, r.opts...)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+// ImageDigest returns the digest of the image that gets synced for ref when
+// selecting platform. For `all`, this is the digest of ref itself. Otherwise,
+// it's the digest of the matching platform image if ref is a multi-platform
+// image. Without platform, the platform of this system is used.
+func (r *Remote) ImageDigest(ref, platform string) (string, error) {
+
+	if platform == "all" {
+		return r.Digest(ref)
+	}
+
+	if platform == "" {
+		platform = runtime.GOOS + "/" + runtime.GOARCH
+	}
+	p, err := gocrv1.ParsePlatform(platform)
+	if err != nil {
+		return "", fmt.Errorf("invalid platform '%s': %v", platform, err)
+	}
+
+	rf, err := r.ref(ref)
+	if err != nil {
+		return "", err
+	}
+	img, err := gocrremote.Image(
+		rf, append(r.opts, gocrremote.WithPlatform(*p))...)
+	if err != nil {
+		return "", err
+	}
+	d, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+// DateCache remembers image creation dates, keyed by manifest digest and
+// platform, for the duration of a sync run. A nil DateCache does not cache.
+type DateCache struct {
+	mutex sync.Mutex
+	dates map[string]time.Time
+}
+
+//
+func NewDateCache() *DateCache {
+	return &DateCache{dates: make(map[string]time.Time)}
+}
+
+//
+func (c *DateCache) get(key string) (time.Time, bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	date, ok := c.dates[key]
+	return date, ok
+}
+
+//
+func (c *DateCache) put(key string, date time.Time) {
+	if c != nil {
+		c.mutex.Lock()
+		c.dates[key] = date
+		c.mutex.Unlock()
+	}
+}
+
+// Created returns the creation date of the image at ref, as recorded in its
+// config. For a multi-platform image, the image matching platform is used, or
+// the one for this system if platform is empty or `all`. Creation dates are
+// kept in cache, so the config is only fetched once for each image.
+func (r *Remote) Created(ref, platform string, cache *DateCache) (
+	time.Time, error) {
+
+	if platform == "" || platform == "all" {
+		platform = runtime.GOOS + "/" + runtime.GOARCH
+	}
+	p, err := gocrv1.ParsePlatform(platform)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"invalid platform '%s': %v", platform, err)
+	}
+
+	rf, err := r.ref(ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	desc, err := gocrremote.Head(rf, r.opts...)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	key := desc.Digest.String() + "|" + platform
+	if date, ok := cache.get(key); ok {
+		return date, nil
+	}
+
+	img, err := gocrremote.Image(
+		rf, append(r.opts, gocrremote.WithPlatform(*p))...)
+	if err != nil {
+		return time.Time{}, err
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"cannot get config of '%s': %v", ref, err)
+	}
+
+	cache.put(key, cfg.Created.Time)
+	return cfg.Created.Time, nil
+}
+
+// Metadata returns the labels from the config, and the annotations from the
+// manifest of the image at ref. For a multi-platform image, labels are taken
+// from the image matching platform, or the one for this system if platform is
+// empty or `all`. Annotations of the index take precedence over those of the
+// platform image in that case.
+func (r *Remote) Metadata(ref, platform string) (
+	labels, annotations map[string]string, err error) {
+
+	if platform == "" || platform == "all" {
+		platform = runtime.GOOS + "/" + runtime.GOARCH
+	}
+	p, err := gocrv1.ParsePlatform(platform)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid platform '%s': %v", platform, err)
+	}
+
+	rf, err := r.ref(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	desc, err := gocrremote.Get(
+		rf, append(r.opts, gocrremote.WithPlatform(*p))...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var indexAnnotations map[string]string
+
+	if desc.MediaType.IsIndex() {
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return nil, nil, err
+		}
+		man, err := idx.IndexManifest()
+		if err != nil {
+			return nil, nil, err
+		}
+		indexAnnotations = man.Annotations
+	}
+
+	img, err := desc.Image() // resolves platform in case of an index
+	if err != nil {
+		return nil, nil, err
+	}
+
+	man, err := img.Manifest()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	annotations = make(map[string]string)
+	for k, v := range man.Annotations {
+		annotations[k] = v
+	}
+	for k, v := range indexAnnotations {
+		annotations[k] = v
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get config of '%s': %v", ref, err)
+	}
+
+	return cfg.Config.Labels, annotations, nil
+}
+
+// Size returns the total size of config and layers of the image at ref, as
+// stated in the manifests, and the largest layer count. For a multi-platform
+// image, this covers the platform images that get synced: all of them for
+// platform `all`, those matching platforms if given, or the image matching
+// platform otherwise. Without platform, the platform of this system is used.
+// Blobs shared between platform images are counted once per image.
+func (r *Remote) Size(ref, platform string, platforms []string) (
+	size int64, layers int, err error) {
+
+	rf, err := r.ref(ref)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	desc, err := gocrremote.Get(rf, r.opts...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !desc.MediaType.IsIndex() {
+		img, err := desc.Image()
+		if err != nil {
+			return 0, 0, err
+		}
+		return imageSize(img)
+	}
+
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		return 0, 0, err
+	}
+	man, err := idx.IndexManifest()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// a single platform is resolved to the first matching image
+	single := len(platforms) == 0 && platform != "all"
+	if single {
+		if platform == "" {
+			platform = runtime.GOOS + "/" + runtime.GOARCH
+		}
+		platforms = []string{platform}
+	}
+
+	var wanted []*gocrv1.Platform
+	for _, p := range platforms {
+		pl, err := gocrv1.ParsePlatform(p)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid platform '%s': %v", p, err)
+		}
+		wanted = append(wanted, pl)
+	}
+
+	for _, m := range man.Manifests {
+
+		if !m.MediaType.IsImage() || !matchesAny(m.Platform, wanted) {
+			continue
+		}
+
+		img, err := idx.Image(m.Digest)
+		if err != nil {
+			return 0, 0, err
+		}
+		s, l, err := imageSize(img)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		size += s
+		if l > layers {
+			layers = l
+		}
+
+		if single {
+			break
+		}
+	}
+
+	return size, layers, nil
+}
+
+// matchesAny checks whether p satisfies any of wanted; if wanted is empty,
+// any p matches
+func matchesAny(p *gocrv1.Platform, wanted []*gocrv1.Platform) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	if p == nil {
+		return false
+	}
+	for _, w := range wanted {
+		if p.Satisfies(*w) {
+			return true
+		}
+	}
+	return false
+}
+
+//
+func imageSize(img gocrv1.Image) (size int64, layers int, err error) {
+	man, err := img.Manifest()
+	if err != nil {
+		return 0, 0, err
+	}
+	size = man.Config.Size
+	for _, l := range man.Layers {
+		size += l.Size
+	}
+	return size, len(man.Layers), nil
+}
+
+// Image returns the image at ref. Manifest and blobs are fetched lazily.
+func (r *Remote) Image(ref string) (gocrv1.Image, error) {
+	rf, err := r.ref(ref)
+	if err != nil {
+		return nil, err
+	}
+	return gocrremote.Image(rf, r.opts...)
+}
+
+// Referrers returns the digests of all manifests referring to the manifest with
+// the given digest in repository repo. If the registry does not support the
+// referrers API, the referrers tag schema is used as fallback.
+func (r *Remote) Referrers(repo, digest string) ([]string, error) {
+
+	d, err := gocrname.NewDigest(
+		fmt.Sprintf(util.FormatDigest, repo, digest), r.nameOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest '%s': %v", digest, err)
+	}
+
+	idx, err := gocrremote.Referrers(d, r.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	man, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, 0, len(man.Manifests))
+	for _, m := range man.Manifests {
+		ret = append(ret, m.Digest.String())
+	}
+	return ret, nil
+}
+
+// Copy copies the manifest and all blobs it references from srcRef, read via
+// this Remote, to trgtRef, written via trgt. Manifests are copied verbatim, so
+// digests are preserved.
+func (r *Remote) Copy(srcRef string, trgt *Remote, trgtRef string) error {
+
+	src, err := r.ref(srcRef)
+	if err != nil {
+		return err
+	}
+	dst, err := trgt.ref(trgtRef)
+	if err != nil {
+		return err
+	}
+
+	desc, err := gocrremote.Get(src, r.opts...)
+	if err != nil {
+		return fmt.Errorf("error getting '%s': %v", srcRef, err)
+	}
+
+	if err := gocrremote.Push(dst, desc, trgt.opts...); err != nil {
+		return fmt.Errorf("error pushing '%s': %v", trgtRef, err)
+	}
+	return nil
+}
+
+// CopyIndex copies the multi-platform image at srcRef, read via this Remote,
+// to trgtRef, written via trgt, keeping only the images for the given
+// platforms. This yields a new index, so the digest at target differs from the
+// source. Platforms not present in the source are skipped with a warning, but
+// at least one of them needs to be present.
+func (r *Remote) CopyIndex(srcRef string, trgt *Remote, trgtRef string,
+	platforms []string) error {
+
+	src, err := r.ref(srcRef)
+	if err != nil {
+		return err
+	}
+	dst, err := trgt.ref(trgtRef)
+	if err != nil {
+		return err
+	}
+
+	wanted := make([]*gocrv1.Platform, 0, len(platforms))
+	for _, p := range platforms {
+		pl, err := gocrv1.ParsePlatform(p)
+		if err != nil {
+			return fmt.Errorf("invalid platform '%s': %v", p, err)
+		}
+		wanted = append(wanted, pl)
+	}
+
+	desc, err := gocrremote.Get(src, r.opts...)
+	if err != nil {
+		return fmt.Errorf("error getting '%s': %v", srcRef, err)
+	}
+	if !desc.MediaType.IsIndex() {
+		return fmt.Errorf("'%s' is not a multi-platform image", srcRef)
+	}
+
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		return fmt.Errorf("error getting index of '%s': %v", srcRef, err)
+	}
+
+	found := make([]bool, len(wanted))
+	filtered := gocrmutate.RemoveManifests(idx, func(d gocrv1.Descriptor) bool {
+		if d.Platform == nil {
+			return true
+		}
+		keep := false
+		for ix, w := range wanted {
+			if d.Platform.Satisfies(*w) {
+				found[ix] = true
+				keep = true
+			}
+		}
+		return !keep
+	})
+
+	// evaluating the filter is what fills in found
+	man, err := filtered.IndexManifest()
+	if err != nil {
+		return fmt.Errorf("error filtering index of '%s': %v", srcRef, err)
+	}
+	if len(man.Manifests) == 0 {
+		return fmt.Errorf(
+			"'%s' contains none of the platforms %v", srcRef, platforms)
+	}
+	for ix, f := range found {
+		if !f {
+			log.WithField("ref", srcRef).Warnf(
+				"platform '%s' not present in source", platforms[ix])
+		}
+	}
+
+	if err := gocrremote.WriteIndex(dst, filtered, trgt.opts...); err != nil {
+		return fmt.Errorf("error pushing '%s': %v", trgtRef, err)
+	}
+	return nil
+}
+
+// IsNotFound checks whether err was caused by the registry not knowing the
+// requested manifest or blob.
+func IsNotFound(err error) bool {
+	var terr *gocrtransport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
+
+// CosignTag returns the tag under which cosign stores an artifact of the type
+// given by suffix for the image with the given digest, e.g. `sha256-{hex}.sig`
+func CosignTag(digest, suffix string) string {
+	return strings.Replace(digest, ":", "-", 1) + suffix
+}
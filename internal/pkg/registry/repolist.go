@@ -19,11 +19,13 @@ package registry
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/xelalexv/dregsy/internal/pkg/auth"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
 //
@@ -34,15 +36,24 @@ const defaultListerCacheDuration = time.Hour
 type ListSourceType string
 
 const (
-	Catalog   ListSourceType = "catalog"
-	DockerHub                = "dockerhub"
-	Index                    = "index"
+	Catalog     ListSourceType = "catalog"
+	DockerHub                  = "dockerhub"
+	Index                      = "index"
+	Harbor                     = "harbor"
+	GitLab                     = "gitlab"
+	GHCR                       = "ghcr"
+	Quay                       = "quay"
+	Artifactory                = "artifactory"
+	Nexus                      = "nexus"
+	GAR                        = "gar"
+	File                       = "file"
 )
 
 //
 func (t ListSourceType) IsValid() bool {
 	switch t {
-	case Catalog, DockerHub, Index:
+	case Catalog, DockerHub, Index, Harbor, GitLab, GHCR, Quay, Artifactory,
+		Nexus, GAR, File:
 		return true
 	}
 	return false
@@ -54,9 +65,22 @@ type ListSource interface {
 	Retrieve(maxItems int) ([]string, error)
 }
 
+// PrefixSource is implemented by list sources that can limit listing to
+// repositories starting with a given prefix on the server side
+type PrefixSource interface {
+	SetPrefix(prefix string)
+}
+
+// TagSource is implemented by list sources that can also provide the tags to
+// sync for the listed repositories
+type TagSource interface {
+	Tags(repo string) []string
+}
+
 //
 func NewRepoList(registry string, insecure bool, typ ListSourceType,
-	config map[string]string, creds *auth.Credentials) (*RepoList, error) {
+	config map[string]string, creds *auth.Credentials, proxy *util.Proxy) (
+	*RepoList, error) {
 
 	list := &RepoList{registry: registry}
 	server := strings.SplitN(registry, ":", 2)[0]
@@ -65,9 +89,9 @@ func NewRepoList(registry string, insecure bool, typ ListSourceType,
 	// listing and searching. These APIs use tokens that are different from the
 	// one used for normal registry actions, so we clone the credentials for list
 	// use. For listing via catalog API, we can use the same credentials as for
-	// push & pull.
+	// push & pull. The file lister does not need any credentials.
 	listCreds := creds
-	if server == "registry.hub.docker.com" {
+	if server == "registry.hub.docker.com" && typ != File {
 		var err error
 		listCreds, err = auth.NewCredentialsFromBasic(
 			creds.Username(), creds.Password())
@@ -84,15 +108,71 @@ func NewRepoList(registry string, insecure bool, typ ListSourceType,
 	switch typ {
 
 	case DockerHub:
-		list.source = newDockerhub(listCreds)
+		list.source = newDockerhub(listCreds, proxy)
 
 	case Index:
 		if filter, ok := config["search"]; ok && filter != "" {
-			list.source = newIndex(registry, filter, insecure, listCreds)
+			list.source = newIndex(
+				registry, filter, insecure, listCreds, proxy)
 		} else {
 			return nil, fmt.Errorf("index lister requires a search expression")
 		}
 
+	case Harbor:
+		list.source = newHarbor(registry, config["project"],
+			config["search"], insecure, listCreds, proxy)
+
+	case GitLab:
+		var err error
+		if list.source, err = newGitLab(registry, config["url"],
+			config["group"], config["project"], config["token"],
+			config["token-type"], insecure, listCreds, proxy); err != nil {
+			return nil, err
+		}
+
+	case GHCR:
+		var err error
+		if list.source, err = newGHCR(config["url"], config["org"],
+			config["user"], config["visibility"], config["token"],
+			listCreds, proxy); err != nil {
+			return nil, err
+		}
+
+	case Quay:
+		var err error
+		if list.source, err = newQuay(registry, config["url"],
+			config["namespace"], config["visibility"], config["token"],
+			insecure, listCreds, proxy); err != nil {
+			return nil, err
+		}
+
+	case Artifactory:
+		var err error
+		if list.source, err = newArtifactory(registry, config["url"],
+			config["repository"], config["mode"], config["prefix"],
+			config["token"], insecure, listCreds, proxy); err != nil {
+			return nil, err
+		}
+
+	case Nexus:
+		list.source = newNexus(registry, config["url"], config["repository"],
+			insecure, listCreds, proxy)
+
+	case GAR:
+		var err error
+		if list.source, err = newGAR(registry, config["url"],
+			config["project"], config["location"], config["repository"],
+			listCreds, proxy); err != nil {
+			return nil, err
+		}
+
+	case File:
+		var err error
+		if list.source, err = newFile(config["path"], config["url"],
+			config["format"], proxy); err != nil {
+			return nil, err
+		}
+
 	case Catalog, "":
 		isECR, public, region, account := IsECR(registry)
 		if isECR {
@@ -108,7 +188,8 @@ func NewRepoList(registry string, insecure bool, typ ListSourceType,
 				list.source = newECR(registry, region, account)
 			}
 		} else {
-			list.source = newCatalog(registry, insecure, IsGCR(server), listCreds)
+			list.source = newCatalog(
+				registry, insecure, IsGCR(server), listCreds, proxy)
 		}
 
 	default:
@@ -123,6 +204,7 @@ func NewRepoList(registry string, insecure bool, typ ListSourceType,
 
 //
 type RepoList struct {
+	mutex         sync.Mutex
 	registry      string
 	source        ListSource
 	maxItems      int
@@ -133,16 +215,32 @@ type RepoList struct {
 
 //
 func (l *RepoList) SetMaxItems(max int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
 	l.maxItems = max
 }
 
 //
 func (l *RepoList) SetCacheDuration(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
 	l.cacheDuration = d
 	l.expiry = time.Now()
 	l.repos = nil
 }
 
+// SetPrefix limits listing to repositories starting with prefix, if the list
+// source supports this. Otherwise, all repositories are listed.
+func (l *RepoList) SetPrefix(prefix string) {
+	if ps, ok := l.source.(PrefixSource); ok {
+		log.WithField("prefix", prefix).Debug("setting lister prefix")
+		l.mutex.Lock()
+		ps.SetPrefix(prefix)
+		l.mutex.Unlock()
+		l.SetCacheDuration(l.cacheDuration) // invalidate
+	}
+}
+
 //
 func (l *RepoList) isCacheValid() bool {
 	return time.Now().Before(l.expiry)
@@ -159,9 +257,13 @@ func (l *RepoList) cacheList(repos []string) {
 	}
 }
 
-//
+// Get returns the repository list, retrieving it from the list source if the
+// cached list has expired. Concurrent callers wait for an ongoing retrieval.
 func (l *RepoList) Get() ([]string, error) {
 
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	if l.isCacheValid() {
 		log.Debug("repository list still valid, re-using")
 		return l.repos, nil
@@ -178,3 +280,14 @@ func (l *RepoList) Get() ([]string, error) {
 		return ret, nil
 	}
 }
+
+// Tags returns the tags listed for repo by the list source, if it provides
+// any. This refers to the last retrieved list.
+func (l *RepoList) Tags(repo string) []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if ts, ok := l.source.(TagSource); ok {
+		return ts.Tags(repo)
+	}
+	return nil
+}
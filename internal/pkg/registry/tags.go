@@ -0,0 +1,64 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package registry
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TagLister lists all tags of a repository. Remote is a TagLister.
+type TagLister interface {
+	ListTags(repo string) ([]string, error)
+}
+
+// TagCache remembers the tags listed per repository, so that mappings with the
+// same source repository list its tags only once during a sync run. A nil
+// TagCache does not cache.
+type TagCache struct {
+	mutex sync.Mutex
+	tags  map[string][]string
+}
+
+//
+func NewTagCache() *TagCache {
+	return &TagCache{tags: make(map[string][]string)}
+}
+
+// ListTags returns the tags of repo, using lister if they are not cached yet.
+func (c *TagCache) ListTags(lister TagLister, repo string) ([]string, error) {
+
+	if c == nil {
+		return lister.ListTags(repo)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if tags, ok := c.tags[repo]; ok {
+		log.WithField("repo", repo).Debug("re-using listed tags")
+		return append([]string(nil), tags...), nil
+	}
+
+	tags, err := lister.ListTags(repo)
+	if err != nil {
+		return nil, err
+	}
+	c.tags[repo] = append([]string(nil), tags...)
+	return tags, nil
+}
@@ -0,0 +1,111 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// Aliases derives the alias tags requested in sync options from the selected
+// tags, leaving out those in unsynced, i.e. tags refused due to limits or that
+// failed to sync. Relays pass all selected tags, including those not synced
+// since already present in target, so that aliases always point to the newest
+// tags. Tags are compared by name, since they may be pinned to digests.
+func Aliases(opt *SyncOptions, selected, unsynced []string) map[string]string {
+
+	if len(opt.Aliases) == 0 {
+		return nil
+	}
+
+	skip := make(map[string]bool, len(unsynced))
+	for _, t := range unsynced {
+		name, _ := util.SplitTag(t)
+		skip[name] = true
+	}
+
+	names := make([]string, 0, len(selected))
+	for _, t := range selected {
+		if name, _ := util.SplitTag(t); name != "" && !skip[name] {
+			names = append(names, name)
+		}
+	}
+
+	return tags.Aliases(names, opt.Aliases)
+}
+
+// PublishAliases points each alias tag in target to the target image of the tag
+// it maps to. Aliases already pointing to the right image are left untouched.
+func PublishAliases(opt *SyncOptions, aliases map[string]string) error {
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	trgt := registry.NewRemote(
+		opt.TrgtAuth, opt.TrgtSkipTLSVerify, opt.TrgtProxy)
+
+	names := make([]string, 0, len(aliases))
+	for a := range aliases {
+		names = append(names, a)
+	}
+	sort.Strings(names)
+
+	errs := false
+
+	for _, a := range names {
+
+		_, trgtRef, err := opt.Refs(aliases[a])
+		if err != nil {
+			log.Error(err)
+			errs = true
+			continue
+		}
+		aliasRef := util.JoinRefAndTag(opt.TrgtRef, a)
+		logger := log.WithFields(log.Fields{"alias": aliasRef, "ref": trgtRef})
+
+		digest, err := trgt.Digest(trgtRef)
+		if err != nil {
+			logger.Errorf("cannot get digest of target image: %v", err)
+			errs = true
+			continue
+		}
+
+		if d, err := trgt.Digest(aliasRef); err == nil && d == digest {
+			logger.Debug("alias up to date")
+			continue
+		}
+
+		logger.Info("publishing alias")
+		if err := trgt.Copy(trgtRef, trgt, aliasRef); err != nil {
+			logger.Error(err)
+			errs = true
+		}
+	}
+
+	if errs {
+		return fmt.Errorf("errors publishing alias tags")
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestPublishAliases(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	trgtRepo := th.NewRegistry(false) + "/mirror/acme/app"
+	trgt := registry.NewRemote("", false, nil)
+
+	selected := []string{"1.3.2", "1.4.0", "1.4.1", "2.0.0-rc.1", "edge"}
+	for _, t := range selected {
+		th.PushImage(trgtRepo+":"+t, th.RandomImage(nil))
+	}
+
+	opt := &SyncOptions{
+		TrgtRef: trgtRepo,
+		Aliases: []string{tags.AliasMajor, tags.AliasMinor, tags.AliasLatest},
+	}
+
+	aliases := Aliases(opt, selected, nil)
+	th.AssertEqualMaps(map[string]string{
+		"1":      "1.4.1",
+		"1.3":    "1.3.2",
+		"1.4":    "1.4.1",
+		"latest": "1.4.1",
+	}, aliases)
+
+	// refused or failed tags are left out, pinned tags compared by name
+	digest := "sha256:" +
+		"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	th.AssertEqualMaps(map[string]string{
+		"1":      "1.4.0",
+		"1.3":    "1.3.2",
+		"1.4":    "1.4.0",
+		"latest": "1.4.0",
+	}, Aliases(opt, []string{"1.3.2@" + digest, "1.4.0", "1.4.1"},
+		[]string{"1.4.1@" + digest}))
+
+	th.AssertNoError(PublishAliases(opt, aliases))
+
+	for alias, tag := range aliases {
+		want, err := trgt.Digest(trgtRepo + ":" + tag)
+		th.AssertNoError(err)
+		got, err := trgt.Digest(trgtRepo + ":" + alias)
+		th.AssertNoError(err)
+		th.AssertEqual(want, got)
+	}
+
+	// aliases pointing to missing images
+	th.AssertError(PublishAliases(opt, map[string]string{"2": "2.0.0"}),
+		"errors publishing alias tags")
+}
@@ -0,0 +1,137 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// suffixes of the tags cosign uses for attaching artifacts to an image
+var cosignSuffixes = []string{".sig", ".att", ".sbom"}
+
+// SyncArtifacts copies the cosign signatures, attestations & SBOMs, and/or the
+// OCI referrers attached to the images of the given tags from source to target,
+// depending on sync options. If tags is empty, all tags of the source are
+// considered. Both relays call this once they synced the images themselves.
+func SyncArtifacts(opt *SyncOptions, tags []string) error {
+
+	if !opt.WithSignatures && !opt.WithReferrers {
+		return nil
+	}
+
+	src := registry.NewRemote(opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+	trgt := registry.NewRemote(
+		opt.TrgtAuth, opt.TrgtSkipTLSVerify, opt.TrgtProxy)
+
+	srcTags, err := src.ListTags(opt.SrcRef)
+	if err != nil {
+		return fmt.Errorf("error listing source tags: %v", err)
+	}
+
+	if len(tags) == 0 {
+		for _, t := range srcTags {
+			if !isArtifactTag(t) {
+				tags = append(tags, t)
+			}
+		}
+	}
+
+	present := make(map[string]bool, len(srcTags))
+	for _, t := range srcTags {
+		present[t] = true
+	}
+
+	errs := false
+
+	for _, t := range tags {
+
+		srcRef, trgtRef, err := opt.Refs(t)
+		if err != nil {
+			log.Error(err)
+			errs = true
+			continue
+		}
+		logger := log.WithField("ref", srcRef)
+
+		digest, err := src.Digest(srcRef)
+		if err != nil {
+			logger.Errorf("cannot get digest of source image: %v", err)
+			errs = true
+			continue
+		}
+
+		// artifacts refer to the source digest; if the relay changed the image
+		// during sync, e.g. by selecting a platform, they won't match in target
+		if d, err := trgt.Digest(trgtRef); err == nil && d != digest {
+			logger.WithFields(log.Fields{"source": digest, "target": d}).Warn(
+				"target digest differs from source, attached artifacts " +
+					"will not match target image")
+		}
+
+		if opt.WithSignatures {
+			for _, s := range cosignSuffixes {
+				tag := registry.CosignTag(digest, s)
+				if !present[tag] {
+					continue
+				}
+				logger.WithField("tag", tag).Info("syncing cosign artifact")
+				if err := src.Copy(
+					util.JoinRefAndTag(opt.SrcRef, tag), trgt,
+					util.JoinRefAndTag(opt.TrgtRef, tag)); err != nil {
+					logger.Error(err)
+					errs = true
+				}
+			}
+		}
+
+		if opt.WithReferrers {
+			refs, err := src.Referrers(opt.SrcRef, digest)
+			if err != nil {
+				logger.Errorf("cannot list referrers: %v", err)
+				errs = true
+				continue
+			}
+			for _, r := range refs {
+				logger.WithField("referrer", r).Info("syncing referrer")
+				if err := src.Copy(
+					util.JoinRefAndTag(opt.SrcRef, r), trgt,
+					util.JoinRefAndTag(opt.TrgtRef, r)); err != nil {
+					logger.Error(err)
+					errs = true
+				}
+			}
+		}
+	}
+
+	if errs {
+		return fmt.Errorf("errors during sync of signatures and/or referrers")
+	}
+
+	return nil
+}
+
+// isArtifactTag checks whether t is a cosign artifact tag, or a tag from the
+// referrers tag schema, i.e. `sha256-{hex}` with an optional suffix
+func isArtifactTag(t string) bool {
+	return strings.HasPrefix(t, "sha256-") && len(t) >= len("sha256-")+64
+}
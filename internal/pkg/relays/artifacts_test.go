@@ -0,0 +1,71 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestSyncArtifacts(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	srcRepo := th.NewRegistry(true) + "/acme/app"
+	trgtRepo := th.NewRegistry(false) + "/mirror/acme/app"
+
+	img := th.PushImage(srcRepo+":1.0.0", th.RandomImage(nil))
+	sig := registry.CosignTag(img.Digest.String(), ".sig")
+	th.PushImage(srcRepo+":"+sig, th.RandomImage(nil))
+	ref := th.PushImage(srcRepo+":sbom", th.RandomImage(img))
+
+	// the relay would have synced the image itself
+	src := registry.NewRemote("", false, nil)
+	trgt := registry.NewRemote("", false, nil)
+	th.AssertNoError(
+		src.Copy(srcRepo+":1.0.0", trgt, trgtRepo+":1.0.0"))
+
+	ts, err := tags.NewTagSet(nil)
+	th.AssertNoError(err)
+
+	opt := &SyncOptions{
+		SrcRef:  srcRepo,
+		TrgtRef: trgtRepo,
+		Tags:    ts,
+	}
+
+	th.AssertNoError(SyncArtifacts(opt, []string{"1.0.0"}))
+	th.AssertFalse(th.HasManifest(trgtRepo + ":" + sig))
+
+	opt.WithSignatures = true
+	th.AssertNoError(SyncArtifacts(opt, []string{"1.0.0"}))
+	th.AssertTrue(th.HasManifest(trgtRepo + ":" + sig))
+	th.AssertFalse(th.HasManifest(trgtRepo + "@" + ref.Digest.String()))
+
+	opt.WithReferrers = true
+	th.AssertNoError(SyncArtifacts(opt, nil))
+	th.AssertTrue(th.HasManifest(trgtRepo + "@" + ref.Digest.String()))
+
+	// target does not support referrers API, so fallback tag must be present
+	refs, err := trgt.Referrers(trgtRepo, img.Digest.String())
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{ref.Digest.String()}, refs)
+}
@@ -17,6 +17,7 @@
 package docker
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -26,6 +27,7 @@ import (
 
 	"github.com/xelalexv/dregsy/internal/pkg/relays"
 	"github.com/xelalexv/dregsy/internal/pkg/relays/skopeo"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
 	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
@@ -41,11 +43,16 @@ type RelayConfig struct {
 type Support struct{}
 
 //-
-func (s *Support) Platform(p string) error {
-	if p == "all" {
+func (s *Support) Platform(p relays.Platforms) error {
+	if p.Single() == "all" {
 		return fmt.Errorf(
 			"relay '%s' does not support mappings with 'platform: all'", RelayID)
 	}
+	if p.IsList() {
+		return fmt.Errorf(
+			"relay '%s' does not support mappings with a list of platforms",
+			RelayID)
+	}
 	return nil
 }
 
@@ -112,27 +119,82 @@ func (r *DockerRelay) Sync(opt *relays.SyncOptions) error {
 		return fmt.Errorf("'Platform: all' sync option not supported")
 	}
 
+	// pulls & pushes are done by the Docker daemon, which uses its own proxy
+	// settings; we can only apply the proxy to tag listing
+	if opt.SrcProxy.IsSet() || opt.TrgtProxy.IsSet() {
+		log.Warn("relay 'docker' uses the Docker daemon's proxy settings " +
+			"for pulling & pushing, location proxy applies only to listing")
+	}
+
 	var tags []string
 	var err error
 
 	// When no tags are specified, a simple docker pull without a tag will get
-	// all tags. So for that case, we don't need to list tags.
-
-	if !opt.Tags.IsEmpty() {
+	// all tags. So for that case, we don't need to list tags, unless we need to
+	// filter by image age, labels & annotations, limit the tag count, verify
+	// signatures for each tag, check the tags in target, record the source
+	// digests, check size limits, or derive alias tags.
+
+	// verification failures and refused tags are logged in detail as they
+	// occur, and reported along with any other errors once the tags that can
+	// be synced are done
+	var verr, lerr error
+	var aliases map[string]string
+
+	if !opt.Tags.IsEmpty() || opt.Tags.NeedsDates() || opt.MaxTags > 0 ||
+		opt.Selector != nil || opt.Verify != nil || opt.ChecksOverwrite() ||
+		opt.Pins != nil || opt.Limits.IsSet() || len(opt.Aliases) > 0 {
 		var certs string
 		reg, _, _ := util.SplitRef(opt.SrcRef)
 		if reg != "" {
 			certs = skopeo.CertsDirForRegistry(reg)
 		}
-		tags, err = opt.Tags.Expand(func() ([]string, error) {
-			return skopeo.ListAllTags(
-				opt.SrcRef, util.DecodeJSONAuth(opt.SrcAuth),
-				certs, opt.SrcSkipTLSVerify)
-		})
+		lister, err := relays.SourceTagLister(opt, certs)
+		if err != nil {
+			return err
+		}
+
+		tags, err = relays.ExpandTags(opt, lister)
 
 		if err != nil {
 			return fmt.Errorf("error expanding tags: %v", err)
 		}
+
+		tags, verr = relays.VerifyTags(opt, tags)
+		if opt.Verify != nil && len(tags) == 0 {
+			// must not fall through to pulling all tags
+			return verr
+		}
+
+		if tags, err = relays.SelectTags(opt, tags); err != nil {
+			return err
+		}
+
+		// fail early, before pulling anything
+		if err := opt.TagTransform.Check(tags); err != nil {
+			return fmt.Errorf("error transforming tags: %v", err)
+		}
+
+		selected := tags
+
+		if tags, err = relays.FilterOverwrite(opt, tags); err != nil {
+			return err
+		}
+
+		var refused []string
+		tags, refused, lerr = relays.CheckLimits(opt, tags)
+		aliases = relays.Aliases(opt, selected, refused)
+
+		// only tags that are actually synced get recorded
+		if tags, err = relays.PinTags(opt, tags); err != nil {
+			return err
+		}
+
+		if len(tags) == 0 {
+			log.Info("no tags to sync")
+			return errors.Join(
+				verr, lerr, relays.PublishAliases(opt, aliases))
+		}
 	}
 
 	if len(tags) == 0 { // pull all tags
@@ -190,7 +252,7 @@ func (r *DockerRelay) Sync(opt *relays.SyncOptions) error {
 	log.WithField("ref", opt.TrgtRef).Info("setting tags for target image")
 
 	// We now tag the source images for the target registry.
-	if err = r.tag(srcImages, opt.TrgtRef); err != nil {
+	if err = r.tag(srcImages, opt.TrgtRef, opt.TagTransform); err != nil {
 		return fmt.Errorf("error setting tags: %v", err)
 	}
 
@@ -209,7 +271,13 @@ func (r *DockerRelay) Sync(opt *relays.SyncOptions) error {
 		return fmt.Errorf("error pushing target image: %v", err)
 	}
 
-	return nil
+	aerr := relays.PublishAliases(opt, aliases)
+
+	// Docker cannot handle signatures & other artifacts, so they are synced
+	// directly between source and target.
+	rerr := relays.SyncArtifacts(opt, tags)
+
+	return errors.Join(verr, lerr, aerr, rerr)
 }
 
 //-
@@ -223,11 +291,21 @@ func (r *DockerRelay) list(ref string) ([]*image, error) {
 }
 
 //-
-func (r *DockerRelay) tag(images []*image, targetRef string) error {
+func (r *DockerRelay) tag(images []*image, targetRef string,
+	transform *tags.Transform) error {
+
+	var all []string
+	for _, img := range images {
+		all = append(all, img.tags...)
+	}
+	if err := transform.Check(all); err != nil {
+		return err
+	}
 
 	for _, img := range images {
 		for _, tag := range img.tags {
 			if tag != "" {
+				tag, _ = transform.Apply(tag) // already checked
 				n, d := util.SplitTag(tag)
 				if n == "" {
 					// Docker does not support pushing by digest only ref; we
@@ -0,0 +1,124 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+	gosync "sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// Budget limits the number of bytes synced during a run of a task. It is shared
+// by all mappings of the task.
+type Budget struct {
+	limit util.Size
+	used  util.Size
+	mutex gosync.Mutex
+}
+
+// NewBudget creates a budget of limit bytes. If limit is not positive, nil is
+// returned, i.e. there is no limit.
+func NewBudget(limit util.Size) *Budget {
+	if limit <= 0 {
+		return nil
+	}
+	return &Budget{limit: limit}
+}
+
+// take reserves size bytes from the budget, and returns false if this would
+// exceed the budget. It is safe to call on nil.
+func (b *Budget) take(size util.Size) bool {
+	if b == nil {
+		return true
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.used+size > b.limit {
+		return false
+	}
+	b.used += size
+	return true
+}
+
+// Limits holds the size limits for the images of a mapping
+type Limits struct {
+	MaxImageSize util.Size
+	MaxLayers    int
+	Budget       *Budget
+}
+
+// IsSet returns true if any limit is set. It is safe to call on nil.
+func (l *Limits) IsSet() bool {
+	return l != nil && (l.MaxImageSize > 0 || l.MaxLayers > 0 || l.Budget != nil)
+}
+
+// CheckLimits checks the images of the given tags against the size limits in
+// sync options, as stated in their manifests, and returns the tags that can be
+// synced, and those that were refused. If images exceeding a limit are refused,
+// an error is returned along with the tags.
+func CheckLimits(opt *SyncOptions, tags []string) (
+	ok, refused []string, err error) {
+
+	if !opt.Limits.IsSet() {
+		return tags, nil, nil
+	}
+
+	src := registry.NewRemote(opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+	ok = make([]string, 0, len(tags))
+
+	for _, t := range tags {
+
+		ref, _ := util.JoinRefsAndTag(opt.SrcRef, "", t)
+		s, layers, err := src.Size(ref, opt.Platform, opt.Platforms)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"cannot get size of '%s': %v", ref, err)
+		}
+		size := util.Size(s)
+
+		logger := log.WithFields(
+			log.Fields{"ref": ref, "size": size, "layers": layers})
+
+		switch {
+		case opt.Limits.MaxImageSize > 0 && size > opt.Limits.MaxImageSize:
+			logger.WithField("limit", opt.Limits.MaxImageSize).Error(
+				"image exceeds maximum size, refusing to sync")
+		case opt.Limits.MaxLayers > 0 && layers > opt.Limits.MaxLayers:
+			logger.WithField("limit", opt.Limits.MaxLayers).Error(
+				"image exceeds maximum layer count, refusing to sync")
+		case !opt.Limits.Budget.take(size):
+			logger.WithField("limit", opt.Limits.Budget.limit).Error(
+				"image exceeds remaining bytes for this run, refusing to sync")
+		default:
+			ok = append(ok, t)
+			continue
+		}
+
+		refused = append(refused, t)
+	}
+
+	if len(refused) > 0 {
+		err = fmt.Errorf("%d of %d images refused due to size limits",
+			len(refused), len(tags))
+	}
+
+	return ok, refused, err
+}
@@ -0,0 +1,78 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+//
+func TestCheckLimits(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	srcRepo := th.NewRegistry(false) + "/acme/app"
+	th.PushImage(srcRepo+":small", th.RandomImage(nil))
+	th.PushIndex(srcRepo+":multi", "linux/amd64", "linux/arm64", "linux/s390x")
+
+	all := []string{"small", "multi"}
+	opt := &SyncOptions{SrcRef: srcRepo, Platform: "linux/amd64"}
+
+	// no limits
+	tags, _, err := CheckLimits(opt, all)
+	th.AssertNoError(err)
+	th.AssertEqualSlices(all, tags)
+
+	// a random test image including config and manifest overhead is ~760 bytes
+	opt.Limits = &Limits{MaxImageSize: 2000}
+	tags, _, err = CheckLimits(opt, all)
+	th.AssertNoError(err)
+	th.AssertEqualSlices(all, tags)
+
+	opt.Platform = "all"
+	tags, refused, err := CheckLimits(opt, all)
+	th.AssertError(err, "1 of 2 images refused")
+	th.AssertEqualSlices([]string{"small"}, tags)
+	th.AssertEqualSlices([]string{"multi"}, refused)
+
+	opt.Platform = ""
+	opt.Platforms = []string{"linux/amd64", "linux/arm64"}
+	tags, _, err = CheckLimits(opt, all)
+	th.AssertNoError(err)
+	th.AssertEqualSlices(all, tags)
+
+	opt.Limits = &Limits{MaxLayers: 1}
+	tags, _, err = CheckLimits(opt, all)
+	th.AssertNoError(err)
+	th.AssertEqualSlices(all, tags)
+
+	// budget is shared across calls
+	opt.Platforms = nil
+	opt.Platform = "linux/amd64"
+	opt.Limits = &Limits{Budget: NewBudget(util.Size(2000))}
+	tags, _, err = CheckLimits(opt, all)
+	th.AssertNoError(err)
+	th.AssertEqualSlices(all, tags)
+	tags, _, err = CheckLimits(opt, all)
+	th.AssertError(err, "2 of 2 images refused")
+	th.AssertEqual(0, len(tags))
+
+	th.AssertNil(NewBudget(0))
+}
@@ -0,0 +1,143 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// overwrite modes, determining what to do with tags already present in target
+const (
+	OverwriteAlways    = "always"
+	OverwriteIfChanged = "if-changed"
+	OverwriteNever     = "never"
+)
+
+// ValidateOverwrite checks whether mode is a valid overwrite mode. An empty
+// mode is valid and means OverwriteAlways.
+func ValidateOverwrite(mode string) error {
+	switch mode {
+	case "", OverwriteAlways, OverwriteIfChanged, OverwriteNever:
+		return nil
+	}
+	return fmt.Errorf("invalid overwrite mode '%s', must be one of '%s', "+
+		"'%s', or '%s'", mode, OverwriteAlways, OverwriteIfChanged,
+		OverwriteNever)
+}
+
+// ChecksOverwrite returns true if the overwrite mode in sync options requires
+// a look at the target before syncing.
+func (o *SyncOptions) ChecksOverwrite() bool {
+	return o.Overwrite == OverwriteIfChanged || o.Overwrite == OverwriteNever
+}
+
+// FilterOverwrite removes those tags from the given list that must not be
+// synced, since they are already present in target, according to the overwrite
+// mode in sync options:
+//
+// - `never`: any tag present in target is dropped. If it points to a different
+//   image than in source, a warning with both digests is logged.
+//
+// - `if-changed`: a tag present in target is dropped if it points to the same
+//   image as in source.
+//
+// - `always`: all tags are kept.
+//
+func FilterOverwrite(opt *SyncOptions, tags []string) ([]string, error) {
+
+	if !opt.ChecksOverwrite() || len(tags) == 0 {
+		return tags, nil
+	}
+
+	src := registry.NewRemote(opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+	trgt := registry.NewRemote(
+		opt.TrgtAuth, opt.TrgtSkipTLSVerify, opt.TrgtProxy)
+
+	trgtTags, err := trgt.ListTags(opt.TrgtRef)
+	if err != nil {
+		if !registry.IsNotFound(err) {
+			return nil, fmt.Errorf("error listing target tags: %v", err)
+		}
+		return tags, nil // target repo does not exist yet
+	}
+
+	// a platform subset yields a new index in target, so we can only compare
+	// against the source index, which will always differ
+	platform := opt.Platform
+	if len(opt.Platforms) > 0 {
+		platform = "all"
+	}
+
+	present := make(map[string]bool, len(trgtTags))
+	for _, t := range trgtTags {
+		present[t] = true
+	}
+
+	ret := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+
+		srcRef, trgtRef, err := opt.Refs(t)
+		if err != nil {
+			return nil, err
+		}
+
+		// digest only refs are immutable, and tags not in target can be synced
+		trgtTag, _ := opt.TagTransform.Apply(t) // error already checked
+		if name, _ := util.SplitTag(trgtTag); name == "" || !present[name] {
+			ret = append(ret, t)
+			continue
+		}
+
+		logger := log.WithField("ref", trgtRef)
+
+		trgtDigest, err := trgt.Digest(trgtRef)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get digest of target image '%s': %v", trgtRef, err)
+		}
+		srcDigest, err := src.ImageDigest(srcRef, platform)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get digest of source image '%s': %v", srcRef, err)
+		}
+
+		if srcDigest == trgtDigest {
+			logger.Debug("target is up to date, skipping")
+			continue
+		}
+
+		logger = logger.WithFields(
+			log.Fields{"source": srcDigest, "target": trgtDigest})
+
+		if opt.Overwrite == OverwriteNever {
+			logger.Warn(
+				"source differs from target, not overwriting target tag")
+			continue
+		}
+
+		logger.Info("source differs from target, overwriting target tag")
+		ret = append(ret, t)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,76 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestFilterOverwrite(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	srcRepo := th.NewRegistry(false) + "/acme/app"
+	trgtRepo := th.NewRegistry(false) + "/mirror/acme/app"
+
+	src := registry.NewRemote("", false, nil)
+	trgt := registry.NewRemote("", false, nil)
+
+	opt := &SyncOptions{
+		SrcRef:    srcRepo,
+		TrgtRef:   trgtRepo,
+		Platform:  "all",
+		Overwrite: OverwriteNever,
+	}
+
+	tags := []string{"1.0.0", "1.1.0", "2.0.0"}
+	for _, t := range tags {
+		th.PushImage(srcRepo+":"+t, th.RandomImage(nil))
+	}
+
+	// target repo does not exist yet
+	filtered, err := FilterOverwrite(opt, tags)
+	th.AssertNoError(err)
+	th.AssertEqualSlices(tags, filtered)
+
+	th.AssertNoError(src.Copy(srcRepo+":1.0.0", trgt, trgtRepo+":1.0.0"))
+	th.AssertNoError(src.Copy(srcRepo+":1.1.0", trgt, trgtRepo+":1.1.0"))
+
+	// upstream retagging
+	th.PushImage(srcRepo+":1.1.0", th.RandomImage(nil))
+
+	filtered, err = FilterOverwrite(opt, tags)
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"2.0.0"}, filtered)
+
+	opt.Overwrite = OverwriteIfChanged
+	filtered, err = FilterOverwrite(opt, tags)
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"1.1.0", "2.0.0"}, filtered)
+
+	opt.Overwrite = OverwriteAlways
+	filtered, err = FilterOverwrite(opt, tags)
+	th.AssertNoError(err)
+	th.AssertEqualSlices(tags, filtered)
+
+	th.AssertNoError(ValidateOverwrite(""))
+	th.AssertError(ValidateOverwrite("sometimes"), "invalid overwrite mode")
+}
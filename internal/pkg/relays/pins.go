@@ -0,0 +1,91 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// Pin records the exact source image from which a target reference was synced
+type Pin struct {
+	Tag    string `yaml:"tag,omitempty"`
+	Source string `yaml:"source"`
+	Target string `yaml:"target"`
+}
+
+// Pins collects the pins for the images synced during a sync run. Relays add to
+// it via PinTags if it is set in sync options.
+type Pins struct {
+	list []*Pin
+}
+
+//
+func (p *Pins) add(pin *Pin) {
+	p.list = append(p.list, pin)
+}
+
+//
+func (p *Pins) List() []*Pin {
+	if p == nil {
+		return nil
+	}
+	return p.list
+}
+
+// PinTags resolves the given tags to the digests of the source images, and
+// returns them pinned to these digests, so that the relay syncs exactly what is
+// recorded. This only happens when collecting pins is requested in sync
+// options, otherwise tags are returned unchanged. Relays call this last, on the
+// tags that are about to be synced, so that tags skipped due to overwrite
+// settings or refused due to limits are not recorded.
+func PinTags(opt *SyncOptions, tags []string) ([]string, error) {
+
+	if opt.Pins == nil {
+		return tags, nil
+	}
+
+	src := registry.NewRemote(opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+	ret := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+
+		srcRef, trgtRef, err := opt.Refs(t)
+		if err != nil {
+			return nil, err
+		}
+
+		name, digest := util.SplitTag(t)
+		if digest == "" {
+			if digest, err = src.Digest(srcRef); err != nil {
+				return nil, fmt.Errorf(
+					"cannot get digest of source image '%s': %v", srcRef, err)
+			}
+		}
+
+		opt.Pins.add(&Pin{
+			Tag:    name,
+			Source: util.JoinRefAndTag(opt.SrcRef, digest),
+			Target: trgtRef,
+		})
+		ret = append(ret, util.JoinTag(name, digest))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,66 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestPinTags(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	srcRepo := th.NewRegistry(false) + "/acme/app"
+	img := th.PushImage(srcRepo+":1.0.0", th.RandomImage(nil))
+	digest := img.Digest.String()
+
+	opt := &SyncOptions{
+		SrcRef:       srcRepo,
+		TrgtRef:      "127.0.0.1:5000/mirror/acme/app",
+		TagTransform: &tags.Transform{Prefix: "upstream-"},
+	}
+	th.AssertNoError(opt.TagTransform.Validate())
+
+	// no pins requested
+	pinned, err := PinTags(opt, []string{"1.0.0"})
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"1.0.0"}, pinned)
+
+	opt.Pins = &Pins{}
+	pinned, err = PinTags(opt, []string{"1.0.0", digest})
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"1.0.0@" + digest, digest}, pinned)
+
+	pins := opt.Pins.List()
+	th.AssertEqual(2, len(pins))
+	th.AssertEqual(Pin{
+		Tag:    "1.0.0",
+		Source: srcRepo + "@" + digest,
+		Target: opt.TrgtRef + ":upstream-1.0.0",
+	}, *pins[0])
+	th.AssertEqual(Pin{
+		Source: srcRepo + "@" + digest,
+		Target: opt.TrgtRef + "@" + digest,
+	}, *pins[1])
+
+	_, err = PinTags(opt, []string{"2.0.0"})
+	th.AssertError(err, "cannot get digest of source image")
+}
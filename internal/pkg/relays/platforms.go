@@ -0,0 +1,128 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// Platforms holds the platform setting of a mapping, which is either a single
+// platform, `all`, or a list of platforms. In YAML, a single platform is given
+// as a plain string, a list as a sequence, even if it contains just one item.
+type Platforms struct {
+	platforms []string
+	list      bool
+}
+
+// NewPlatforms creates a list of platforms, as if given as a YAML sequence.
+func NewPlatforms(platforms ...string) Platforms {
+	return Platforms{platforms: platforms, list: true}
+}
+
+//
+func (p *Platforms) UnmarshalYAML(unmarshal func(interface{}) error) error {
+
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*p = Platforms{}
+		if single = strings.TrimSpace(single); single != "" {
+			p.platforms = []string{single}
+		}
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*p = NewPlatforms(list...)
+	return nil
+}
+
+//
+func (p Platforms) Validate() error {
+
+	if !p.IsList() {
+		return nil
+	}
+
+	if len(p.platforms) == 0 {
+		return fmt.Errorf("platform list must not be empty")
+	}
+
+	seen := make(map[string]bool, len(p.platforms))
+
+	for _, pl := range p.platforms {
+		if pl == "all" {
+			return fmt.Errorf("'all' cannot be part of a platform list")
+		}
+		if os, arch, _ := util.SplitPlatform(pl); os == "" || arch == "" {
+			return fmt.Errorf(
+				"platform '%s' in list needs to specify OS and architecture", pl)
+		}
+		if seen[pl] {
+			return fmt.Errorf("duplicate platform '%s' in list", pl)
+		}
+		seen[pl] = true
+	}
+
+	return nil
+}
+
+// Single returns the platform if a single one is set, and an empty string if
+// none is set or this is a list of platforms.
+func (p Platforms) Single() string {
+	if !p.list && len(p.platforms) == 1 {
+		return p.platforms[0]
+	}
+	return ""
+}
+
+// List returns the platforms if this is a list, and nil otherwise.
+func (p Platforms) List() []string {
+	if p.IsList() {
+		return p.platforms
+	}
+	return nil
+}
+
+// IsList returns true if this is a list of platforms, i.e. a multi-platform
+// image containing just these platforms is to be synced from a multi-platform
+// source image. This is also the case for a list with a single platform.
+func (p Platforms) IsList() bool {
+	return p.list
+}
+
+// SyncPlatforms syncs the multi-platform image at src to trgt, keeping only the
+// platforms listed in sync options. Relays use this when a mapping selects a
+// subset of platforms, since neither Skopeo nor Docker can do that on their own.
+func SyncPlatforms(opt *SyncOptions, src, trgt string) error {
+
+	log.WithFields(log.Fields{"ref": src, "platforms": opt.Platforms}).Debug(
+		"syncing platform subset")
+
+	return registry.NewRemote(
+		opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy).CopyIndex(src,
+		registry.NewRemote(opt.TrgtAuth, opt.TrgtSkipTLSVerify, opt.TrgtProxy),
+		trgt, opt.Platforms)
+}
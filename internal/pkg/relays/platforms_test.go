@@ -0,0 +1,83 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestPlatformsUnmarshal(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	for _, tc := range []struct {
+		yaml   string
+		single string
+		list   []string
+	}{
+		{"platform: linux/arm64", "linux/arm64", nil},
+		{"platform: all", "all", nil},
+		{"platform: [linux/amd64, linux/arm64]",
+			"", []string{"linux/amd64", "linux/arm64"}},
+		{"platform: [linux/arm64]", "", []string{"linux/arm64"}},
+		{"other: x", "", nil},
+	} {
+		var m struct {
+			Platform Platforms `yaml:"platform"`
+		}
+		th.AssertNoError(yaml.Unmarshal([]byte(tc.yaml), &m))
+		th.AssertEqual(tc.single, m.Platform.Single())
+		th.AssertEqualSlices(tc.list, m.Platform.List())
+		th.AssertEqual(tc.list != nil, m.Platform.IsList())
+		th.AssertNoError(m.Platform.Validate())
+	}
+
+	th.AssertError(NewPlatforms("linux/amd64", "linux/amd64").Validate(),
+		"duplicate platform")
+	th.AssertError(NewPlatforms("linux/amd64", "arm64").Validate(),
+		"needs to specify OS and architecture")
+	th.AssertError(NewPlatforms().Validate(), "must not be empty")
+}
+
+//
+func TestSyncPlatforms(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	srcRepo := th.NewRegistry(false) + "/acme/app"
+	trgtRepo := th.NewRegistry(false) + "/mirror/acme/app"
+
+	th.PushIndex(srcRepo+":1.0.0",
+		"linux/amd64", "linux/arm64/v8", "linux/s390x")
+
+	opt := &SyncOptions{
+		Platforms: []string{"linux/amd64", "linux/arm64", "windows/amd64"}}
+	th.AssertNoError(
+		SyncPlatforms(opt, srcRepo+":1.0.0", trgtRepo+":1.0.0"))
+
+	th.AssertEqualSlices([]string{"linux/amd64", "linux/arm64/v8"},
+		th.IndexPlatforms(trgtRepo+":1.0.0"))
+
+	opt.Platforms = []string{"windows/amd64"}
+	th.AssertError(SyncPlatforms(opt, srcRepo+":1.0.0", trgtRepo+":1.0.0"),
+		"contains none of the platforms")
+}
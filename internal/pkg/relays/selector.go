@@ -0,0 +1,139 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// RegexpPrefix marks a selector value as regular expression
+const RegexpPrefix = "regex:"
+
+// Selector selects images by their labels and annotations. An image is selected
+// if all given labels and annotations are present with matching values.
+type Selector struct {
+	labels      map[string]*util.Regex
+	annotations map[string]*util.Regex
+}
+
+// NewSelector creates a Selector for the given labels and annotations. Values
+// are matched verbatim, unless they start with `regex:`. If both labels and
+// annotations are empty, nil is returned.
+func NewSelector(labels, annotations map[string]string) (*Selector, error) {
+
+	if len(labels) == 0 && len(annotations) == 0 {
+		return nil, nil
+	}
+
+	ret := &Selector{}
+	var err error
+
+	if ret.labels, err = compileSelector(labels); err != nil {
+		return nil, fmt.Errorf("invalid label selector: %v", err)
+	}
+	if ret.annotations, err = compileSelector(annotations); err != nil {
+		return nil, fmt.Errorf("invalid annotation selector: %v", err)
+	}
+
+	return ret, nil
+}
+
+//
+func compileSelector(sel map[string]string) (map[string]*util.Regex, error) {
+
+	ret := make(map[string]*util.Regex, len(sel))
+
+	for k, v := range sel {
+		// grouping keeps a leading `!` from being taken as inversion
+		expr := "(?:" + regexp.QuoteMeta(v) + ")"
+		if strings.HasPrefix(v, RegexpPrefix) {
+			expr = strings.TrimSpace(v[len(RegexpPrefix):])
+		}
+		r, err := util.NewRegex(expr)
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %v", k, err)
+		}
+		ret[k] = r
+	}
+
+	return ret, nil
+}
+
+// mismatch returns the first key in sel for which meta has no matching value,
+// or an empty string if all keys match
+func mismatch(sel map[string]*util.Regex, meta map[string]string) string {
+
+	keys := make([]string, 0, len(sel))
+	for k := range sel {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if v, ok := meta[k]; !ok || !sel[k].Matches(v) {
+			return k
+		}
+	}
+	return ""
+}
+
+// SelectTags returns those of the given tags whose images are selected by the
+// selector in sync options. If no selector is set, all tags are returned.
+func SelectTags(opt *SyncOptions, tags []string) ([]string, error) {
+
+	if opt.Selector == nil {
+		return tags, nil
+	}
+
+	src := registry.NewRemote(opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+	ret := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+
+		ref, _ := util.JoinRefsAndTag(opt.SrcRef, "", t)
+		labels, annotations, err := src.Metadata(ref, opt.Platform)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get labels & annotations of '%s': %v", ref, err)
+		}
+
+		logger := log.WithField("ref", ref)
+
+		if k := mismatch(opt.Selector.labels, labels); k != "" {
+			logger.WithField("label", k).Debug("label does not match, skipping")
+			continue
+		}
+		if k := mismatch(opt.Selector.annotations, annotations); k != "" {
+			logger.WithField("annotation", k).Debug(
+				"annotation does not match, skipping")
+			continue
+		}
+
+		ret = append(ret, t)
+	}
+
+	log.Debugf("tags selected by labels & annotations: %v", ret)
+	return ret, nil
+}
@@ -0,0 +1,81 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"testing"
+
+	gocrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestSelectTags(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	srcRepo := th.NewRegistry(false) + "/acme/shared"
+
+	push := func(tag string, labels, annotations map[string]string) {
+		img, err := mutate.Config(th.RandomImage(nil),
+			gocrv1.Config{Labels: labels})
+		th.AssertNoError(err)
+		th.PushImage(srcRepo+":"+tag,
+			mutate.Annotations(img, annotations).(gocrv1.Image))
+	}
+
+	push("1.0.0", map[string]string{"release": "approved",
+		"org.opencontainers.image.vendor": "Acme"}, nil)
+	push("1.1.0", map[string]string{"release": "approved",
+		"org.opencontainers.image.vendor": "Acme"},
+		map[string]string{"org.opencontainers.image.version": "1.1.0"})
+	push("1.2.0-rc1", map[string]string{"release": "pending",
+		"org.opencontainers.image.vendor": "Acme"}, nil)
+	push("other", map[string]string{"release": "approved"}, nil)
+
+	all := []string{"1.0.0", "1.1.0", "1.2.0-rc1", "other"}
+	opt := &SyncOptions{SrcRef: srcRepo}
+
+	for _, tc := range []struct {
+		labels      map[string]string
+		annotations map[string]string
+		expected    []string
+	}{
+		{nil, nil, all},
+		{map[string]string{"release": "approved"}, nil,
+			[]string{"1.0.0", "1.1.0", "other"}},
+		{map[string]string{"release": "approved",
+			"org.opencontainers.image.vendor": "Acme"}, nil,
+			[]string{"1.0.0", "1.1.0"}},
+		{map[string]string{"release": "regex: !approved"}, nil,
+			[]string{"1.2.0-rc1"}},
+		{nil, map[string]string{
+			"org.opencontainers.image.version": "regex: 1\\..+"},
+			[]string{"1.1.0"}},
+	} {
+		var err error
+		opt.Selector, err = NewSelector(tc.labels, tc.annotations)
+		th.AssertNoError(err)
+		tags, err := SelectTags(opt, all)
+		th.AssertNoError(err)
+		th.AssertEqualSlices(tc.expected, tags)
+	}
+
+	_, err := NewSelector(map[string]string{"release": "regex: ("}, nil)
+	th.AssertError(err, "invalid label selector: 'release'")
+}
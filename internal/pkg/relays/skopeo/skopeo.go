@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"strings"
 
@@ -54,9 +55,11 @@ func CertsDirForRegistry(r string) string {
 }
 
 //
-func ListAllTags(ref, creds, certDir string, skipTLSVerify bool) ([]string, error) {
+func ListAllTags(ref, creds, certDir string, skipTLSVerify bool,
+	proxy *util.Proxy) ([]string, error) {
 
-	ret, err := info([]string{"list-tags"}, ref, creds, certDir, skipTLSVerify)
+	ret, err := info(
+		[]string{"list-tags"}, ref, creds, certDir, skipTLSVerify, proxy)
 	if err != nil {
 		return nil,
 			fmt.Errorf("error listing image tags for ref '%s': %v", ref, err)
@@ -70,15 +73,16 @@ func ListAllTags(ref, creds, certDir string, skipTLSVerify bool) ([]string, erro
 }
 
 //
-func Inspect(ref, platform, format, creds, certDir string, skipTLSVerify bool) (
-	string, error) {
+func Inspect(ref, platform, format, creds, certDir string, skipTLSVerify bool,
+	proxy *util.Proxy) (string, error) {
 
 	cmd := addPlatformOverrides([]string{"inspect"}, platform)
 	if format != "" {
 		cmd = append(cmd, fmt.Sprintf("--format=%s", format))
 	}
 
-	if insp, err := info(cmd, ref, creds, certDir, skipTLSVerify); err != nil {
+	if insp, err := info(
+		cmd, ref, creds, certDir, skipTLSVerify, proxy); err != nil {
 		return "", fmt.Errorf(
 			"error inspecting image for ref '%s': %v", ref, err)
 	} else {
@@ -87,8 +91,8 @@ func Inspect(ref, platform, format, creds, certDir string, skipTLSVerify bool) (
 }
 
 //
-func info(cmd []string, ref, creds, certDir string, skipTLSVerify bool) (
-	[]byte, error) {
+func info(cmd []string, ref, creds, certDir string, skipTLSVerify bool,
+	proxy *util.Proxy) ([]byte, error) {
 
 	if skipTLSVerify {
 		cmd = append(cmd, "--tls-verify=false")
@@ -107,7 +111,8 @@ func info(cmd []string, ref, creds, certDir string, skipTLSVerify bool) (
 	bufOut := new(bytes.Buffer)
 	bufErr := new(bytes.Buffer)
 
-	if err := runSkopeo(bufOut, bufErr, true, cmd...); err != nil {
+	if err := runSkopeo(
+		bufOut, bufErr, true, proxy.Env(), cmd...); err != nil {
 		return nil, fmt.Errorf("%s, %v", bufErr.String(), err)
 	}
 
@@ -148,9 +153,13 @@ func chooseOutStream(out io.Writer, verbose, isErrorStream bool) io.Writer {
 }
 
 //
-func runSkopeo(outWr, errWr io.Writer, verbose bool, args ...string) error {
+func runSkopeo(outWr, errWr io.Writer, verbose bool, env []string,
+	args ...string) error {
 
 	cmd := exec.Command(skopeoBinary, args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	cmd.Stdout = chooseOutStream(outWr, verbose, false)
 	cmd.Stderr = chooseOutStream(errWr, verbose, true)
@@ -18,8 +18,10 @@ package skopeo
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
@@ -39,7 +41,7 @@ type RelayConfig struct {
 type Support struct{}
 
 //
-func (s *Support) Platform(p string) error {
+func (s *Support) Platform(p relays.Platforms) error {
 	return nil
 }
 
@@ -72,7 +74,7 @@ func NewSkopeoRelay(conf *RelayConfig, out io.Writer) *SkopeoRelay {
 func (r *SkopeoRelay) Prepare() error {
 
 	bufOut := new(bytes.Buffer)
-	if err := runSkopeo(bufOut, nil, true, "--version"); err != nil {
+	if err := runSkopeo(bufOut, nil, true, nil, "--version"); err != nil {
 		return fmt.Errorf("cannot execute skopeo: %v", err)
 	}
 
@@ -124,42 +126,159 @@ func (r *SkopeoRelay) Sync(opt *relays.SyncOptions) error {
 		cmd = append(cmd, fmt.Sprintf("--dest-creds=%s", destCreds))
 	}
 
-	tags, err := opt.Tags.Expand(func() ([]string, error) {
-		return ListAllTags(opt.SrcRef, srcCreds, srcCertDir, opt.SrcSkipTLSVerify)
-	})
+	env, err := copyProxyEnv(opt)
+	if err != nil {
+		return err
+	}
+
+	lister, err := relays.SourceTagLister(opt, srcCertDir)
+	if err != nil {
+		return err
+	}
+
+	tags, err := relays.ExpandTags(opt, lister)
 
 	if err != nil {
 		return fmt.Errorf("error expanding tags: %v", err)
 	}
 
-	errs := false
+	// verification failures and refused tags are logged in detail as they
+	// occur, and reported along with any other errors once the tags that can
+	// be synced are done
+	tags, verr := relays.VerifyTags(opt, tags)
 
-	for _, t := range tags {
+	if tags, err = relays.SelectTags(opt, tags); err != nil {
+		return err
+	}
 
-		log.WithFields(
-			log.Fields{"tag": t, "platform": opt.Platform}).Info("syncing tag")
+	if err := opt.TagTransform.Check(tags); err != nil {
+		return fmt.Errorf("error transforming tags: %v", err)
+	}
 
-		src, trgt := util.JoinRefsAndTag(opt.SrcRef, opt.TrgtRef, t)
-		rc := append(cmd,
-			fmt.Sprintf("docker://%s", src), fmt.Sprintf("docker://%s", trgt))
+	selected := tags
 
-		switch opt.Platform {
-		case "":
-		case "all":
-			rc = append(rc, "--all")
-		default:
-			rc = addPlatformOverrides(rc, opt.Platform)
-		}
+	if tags, err = relays.FilterOverwrite(opt, tags); err != nil {
+		return err
+	}
+
+	tags, refused, lerr := relays.CheckLimits(opt, tags)
+
+	// only tags that are actually synced get recorded
+	if tags, err = relays.PinTags(opt, tags); err != nil {
+		return err
+	}
 
-		if err := runSkopeo(r.wrOut, r.wrOut, opt.Verbose, rc...); err != nil {
+	if len(tags) == 0 {
+		log.Info("no tags to sync")
+	}
+
+	var synced, failed []string
+
+	for _, t := range tags {
+		if err := r.syncTag(opt, cmd, env, t); err != nil {
 			log.Error(err)
-			errs = true
+			failed = append(failed, t)
+			continue
 		}
+		synced = append(synced, t)
 	}
 
-	if errs {
-		return fmt.Errorf("errors during sync")
+	var serr error
+	if len(failed) > 0 {
+		serr = fmt.Errorf(
+			"%d of %d tags failed to sync", len(failed), len(tags))
 	}
 
-	return nil
+	// aliases & attached artifacts are needed for the tags that were synced,
+	// regardless of errors for other tags, e.g. by admission controllers
+	aliases := relays.Aliases(opt, selected, append(refused, failed...))
+	aerr := relays.PublishAliases(opt, aliases)
+
+	var rerr error
+	if len(synced) > 0 {
+		rerr = relays.SyncArtifacts(opt, synced)
+	}
+
+	return errors.Join(verr, lerr, serr, aerr, rerr)
+}
+
+// syncTag copies the image of tag from source to target, using the given skopeo
+// copy command and environment.
+func (r *SkopeoRelay) syncTag(opt *relays.SyncOptions, cmd, env []string,
+	tag string) error {
+
+	log.WithFields(log.Fields{"tag": tag, "platform": opt.Platform,
+		"platforms": opt.Platforms}).Info("syncing tag")
+
+	src, trgt, err := opt.Refs(tag)
+	if err != nil {
+		return err
+	}
+
+	// skopeo can copy either all platforms or a single one, so subsets are
+	// handled by us
+	if len(opt.Platforms) > 0 {
+		return relays.SyncPlatforms(opt, src, trgt)
+	}
+
+	rc := append(cmd[:len(cmd):len(cmd)],
+		fmt.Sprintf("docker://%s", src), fmt.Sprintf("docker://%s", trgt))
+
+	switch opt.Platform {
+	case "":
+	case "all":
+		rc = append(rc, "--all")
+	default:
+		rc = addPlatformOverrides(rc, opt.Platform)
+	}
+
+	// attached artifacts refer to the image digest, so it must not change
+	if opt.WithSignatures || opt.WithReferrers {
+		rc = append(rc, "--preserve-digests")
+	}
+
+	return runSkopeo(r.wrOut, r.wrOut, opt.Verbose, env, rc...)
+}
+
+// copyProxyEnv determines the proxy environment for a skopeo copy. Since skopeo
+// talks to both source and target from within the same process, there can only
+// be one proxy. If just one side has a proxy, the other side's registry is
+// added to the no-proxy list, so it is reached directly.
+func copyProxyEnv(opt *relays.SyncOptions) ([]string, error) {
+
+	src, trgt := opt.SrcProxy, opt.TrgtProxy
+
+	if !src.IsSet() && !trgt.IsSet() {
+		return nil, nil
+	}
+
+	if src.IsSet() && trgt.IsSet() && src.URL != trgt.URL {
+		return nil, fmt.Errorf(
+			"relay '%s' does not support different proxies for source and "+
+				"target", RelayID)
+	}
+
+	var noProxy []string
+	for _, p := range []*util.Proxy{src, trgt} {
+		if p.IsSet() && p.NoProxy != "" {
+			noProxy = append(noProxy, p.NoProxy)
+		}
+	}
+
+	proxy, direct := src, opt.TrgtRef
+	if !src.IsSet() {
+		proxy, direct = trgt, opt.SrcRef
+	}
+
+	if !src.IsSet() || !trgt.IsSet() { // other side goes direct
+		if reg, _, _ := util.SplitRef(direct); reg != "" {
+			noProxy = append(noProxy, reg)
+		}
+	}
+
+	merged, err := util.NewProxy(proxy.URL, strings.Join(noProxy, ","))
+	if err != nil {
+		return nil, err
+	}
+	return merged.Env(), nil
 }
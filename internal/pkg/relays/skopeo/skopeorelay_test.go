@@ -0,0 +1,164 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package skopeo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/relays"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+	"github.com/xelalexv/dregsy/internal/pkg/verify"
+)
+
+//
+func TestSyncPartialFailure(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	relay, _ := newFakeRelay(th)
+
+	srcRepo := th.NewRegistry(false) + "/acme/app"
+	trgtRepo := th.NewRegistry(false) + "/mirror/acme/app"
+
+	var sigs []string
+	for _, tag := range []string{"1.0", "broken"} {
+		img := th.PushImage(srcRepo+":"+tag, th.RandomImage(nil))
+		sig := registry.CosignTag(img.Digest.String(), ".sig")
+		th.PushImage(srcRepo+":"+sig, th.RandomImage(nil))
+		sigs = append(sigs, sig)
+	}
+
+	ts, err := tags.NewTagSet([]string{"1.0", "broken"})
+	th.AssertNoError(err)
+
+	opt := &relays.SyncOptions{
+		SrcRef:         srcRepo,
+		TrgtRef:        trgtRepo,
+		Tags:           ts,
+		WithSignatures: true,
+	}
+
+	// copying 'broken' fails, but signature of '1.0' still needs to be synced
+	th.AssertError(relay.Sync(opt), "1 of 2 tags failed to sync")
+	th.AssertTrue(th.HasManifest(trgtRepo + ":" + sigs[0]))
+	th.AssertFalse(th.HasManifest(trgtRepo + ":" + sigs[1]))
+}
+
+//
+func TestSyncPartialVerification(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	relay, copied := newFakeRelay(th)
+
+	srcRepo := th.NewRegistry(false) + "/acme/app"
+	trgtRepo := th.NewRegistry(false) + "/mirror/acme/app"
+
+	key := th.NewKey()
+	policy := &verify.Policy{Key: th.WritePublicKey(th.TempDir(), "k.pub", key)}
+	th.AssertNoError(policy.Validate())
+
+	signed := th.PushImage(srcRepo+":signed", th.RandomImage(nil))
+	sig := th.PushSignature(srcRepo, signed.Digest, key, nil)
+	unsigned := th.PushImage(srcRepo+":unsigned", th.RandomImage(nil))
+	bogus := th.PushSignature(srcRepo, unsigned.Digest, th.NewKey(), nil)
+
+	ts, err := tags.NewTagSet([]string{"signed", "unsigned"})
+	th.AssertNoError(err)
+
+	opt := &relays.SyncOptions{
+		SrcRef:         srcRepo,
+		TrgtRef:        trgtRepo,
+		Tags:           ts,
+		Verify:         policy,
+		WithSignatures: true,
+	}
+
+	// only the verified tag is synced, but along with its signature
+	th.AssertError(relay.Sync(opt),
+		"1 of 2 images failed signature verification")
+
+	log, err := os.ReadFile(copied)
+	th.AssertNoError(err)
+	th.AssertTrue(strings.Contains(
+		string(log), srcRepo+"@"+signed.Digest.String()))
+	th.AssertFalse(strings.Contains(string(log), unsigned.Digest.String()))
+	th.AssertFalse(strings.Contains(string(log), srcRepo+":unsigned"))
+
+	th.AssertTrue(th.HasManifest(trgtRepo + ":" + sig))
+	th.AssertFalse(th.HasManifest(trgtRepo + ":" + bogus))
+}
+
+//
+func TestSyncPins(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	relay, _ := newFakeRelay(th)
+
+	srcRepo := th.NewRegistry(false) + "/acme/app"
+	trgtRepo := th.NewRegistry(false) + "/mirror/acme/app"
+
+	th.PushImage(srcRepo+":1.0", th.RandomImage(nil))
+	th.PushImage(trgtRepo+":1.0", th.RandomImage(nil))
+	th.PushImage(srcRepo+":2.0", th.RandomImage(nil))
+	th.PushIndex(srcRepo+":multi", "linux/amd64", "linux/arm64", "linux/s390x")
+
+	ts, err := tags.NewTagSet([]string{"1.0", "2.0", "multi"})
+	th.AssertNoError(err)
+
+	opt := &relays.SyncOptions{
+		SrcRef:    srcRepo,
+		TrgtRef:   trgtRepo,
+		Tags:      ts,
+		Platform:  "all",
+		Overwrite: relays.OverwriteNever,
+		Limits:    &relays.Limits{MaxImageSize: 2000},
+		Pins:      &relays.Pins{},
+	}
+
+	// '1.0' is skipped since present in target, 'multi' is refused for its
+	// size, so only '2.0' gets pinned
+	th.AssertError(relay.Sync(opt), "1 of 2 images refused")
+
+	pins := opt.Pins.List()
+	th.AssertEqual(1, len(pins))
+	th.AssertEqual("2.0", pins[0].Tag)
+	th.AssertEqual(trgtRepo+":2.0", pins[0].Target)
+}
+
+// newFakeRelay creates a relay with a fake skopeo binary that pretends to copy
+// images, but fails for tags named 'broken'. The arguments of each call are
+// written to the returned log file.
+func newFakeRelay(th *test.TestHelper) (*SkopeoRelay, string) {
+
+	dir := th.TempDir()
+	binary := filepath.Join(dir, "skopeo")
+	th.AssertNoError(os.WriteFile(binary, []byte(`#!/bin/sh
+echo "$@" >> "$(dirname "$0")/copied"
+for a in "$@"; do
+	case "$a" in *:broken) exit 1;; esac
+done
+`), 0755))
+
+	orig := skopeoBinary
+	th.Cleanup(func() { skopeoBinary = orig })
+	return NewSkopeoRelay(&RelayConfig{Binary: binary}, nil),
+		filepath.Join(dir, "copied")
+}
@@ -0,0 +1,78 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// ExpandTags expands the tag set in sync options, using lister for listing all
+// source tags. Listed tags are kept in the tag cache of the sync options, if
+// set. Creation dates of images needed for age based filters are retrieved via
+// the registry API, and kept in the date cache of the sync options, if set. If
+// more tags than allowed by the tag limit in sync options are selected, an
+// error is returned.
+func ExpandTags(opt *SyncOptions, lister registry.TagLister) (
+	[]string, error) {
+
+	var created func(tag string) (time.Time, error)
+
+	if opt.Tags.NeedsDates() {
+		src := registry.NewRemote(
+			opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+		created = func(tag string) (time.Time, error) {
+			ref, _ := util.JoinRefsAndTag(opt.SrcRef, "", tag)
+			return src.Created(ref, opt.Platform, opt.DateCache)
+		}
+	}
+
+	tags, err := opt.Tags.Expand(func() ([]string, error) {
+		ret, err := opt.TagCache.ListTags(lister, opt.SrcRef)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error listing image tags for ref '%s': %v", opt.SrcRef, err)
+		}
+		return ret, nil
+	}, created)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.MaxTags > 0 && len(tags) > opt.MaxTags {
+		return nil, fmt.Errorf(
+			"%d tags selected, exceeding 'max-tags' limit of %d, aborting",
+			len(tags), opt.MaxTags)
+	}
+
+	return tags, nil
+}
+
+// SourceTagLister returns a TagLister for the source of sync options, trusting
+// the certificates in certDir.
+func SourceTagLister(opt *SyncOptions, certDir string) (
+	registry.TagLister, error) {
+
+	ret := registry.NewRemote(opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+	if err := ret.UseCertDir(certDir); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,147 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	gocrregistry "github.com/google/go-containerregistry/pkg/registry"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestExpandTagsLimit(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	ts, err := tags.NewTagSet([]string{"regex: 1\\..+"})
+	th.AssertNoError(err)
+
+	lister := &countingLister{
+		lister: tagList{"1.0", "1.1", "1.2", "2.0"}}
+
+	opt := &SyncOptions{Tags: ts}
+	expanded, err := ExpandTags(opt, lister)
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"1.0", "1.1", "1.2"}, expanded)
+
+	opt.MaxTags = 3
+	_, err = ExpandTags(opt, lister)
+	th.AssertNoError(err)
+
+	opt.MaxTags = 2
+	_, err = ExpandTags(opt, lister)
+	th.AssertError(err, "3 tags selected, exceeding 'max-tags' limit of 2")
+}
+
+//
+func TestExpandTagsRegistry(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	repo := th.NewRegistry(false) + "/acme/app"
+	all := []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0", "edge"}
+	img := th.RandomImage(nil)
+	for _, t := range all {
+		th.PushImage(repo+":"+t, img)
+	}
+
+	lister, err := SourceTagLister(&SyncOptions{}, "")
+	th.AssertNoError(err)
+	counting := &countingLister{lister: lister}
+
+	ts, err := tags.NewTagSet(nil)
+	th.AssertNoError(err)
+	opt := &SyncOptions{
+		SrcRef: repo, Tags: ts, TagCache: registry.NewTagCache()}
+
+	listed, err := ExpandTags(opt, counting)
+	th.AssertNoError(err)
+	sort.Strings(listed)
+	th.AssertEqualSlices(all, listed)
+
+	// second mapping with same source repo is served from cache
+	opt.Tags, err = tags.NewTagSet([]string{"semver: >=1.1.0 <2.0.0"})
+	th.AssertNoError(err)
+	listed, err = ExpandTags(opt, counting)
+	th.AssertNoError(err)
+	th.AssertEqualSlices([]string{"1.1.0", "1.2.0"}, listed)
+	th.AssertEqual(1, counting.calls)
+
+	opt.SrcRef = repo + "/missing"
+	_, err = ExpandTags(opt, counting)
+	th.AssertError(err, "error listing image tags")
+}
+
+//
+func TestSourceTagListerCertDir(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	srv := httptest.NewTLSServer(gocrregistry.New())
+	defer srv.Close()
+	repo := strings.TrimPrefix(srv.URL, "https://") + "/acme/app"
+
+	lister, err := SourceTagLister(&SyncOptions{}, t.TempDir())
+	th.AssertNoError(err)
+	_, err = lister.ListTags(repo)
+	th.AssertError(err, "certificate")
+
+	dir := t.TempDir()
+	th.AssertNoError(os.WriteFile(filepath.Join(dir, "ca.crt"),
+		pem.EncodeToMemory(&pem.Block{
+			Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0644))
+
+	lister, err = SourceTagLister(&SyncOptions{}, dir)
+	th.AssertNoError(err)
+	_, err = lister.ListTags(repo)
+	th.AssertError(err, "NAME_UNKNOWN")
+
+	th.AssertNoError(os.WriteFile(
+		filepath.Join(dir, "bad.crt"), []byte("garbage"), 0644))
+	_, err = SourceTagLister(&SyncOptions{}, dir)
+	th.AssertError(err, "no valid CA certificate")
+}
+
+//
+type tagList []string
+
+//
+func (l tagList) ListTags(repo string) ([]string, error) {
+	return l, nil
+}
+
+//
+type countingLister struct {
+	lister registry.TagLister
+	calls  int
+}
+
+//
+func (l *countingLister) ListTags(repo string) ([]string, error) {
+	l.calls++
+	return l.lister.ListTags(repo)
+}
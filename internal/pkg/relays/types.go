@@ -17,7 +17,10 @@
 package relays
 
 import (
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
 	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+	"github.com/xelalexv/dregsy/internal/pkg/verify"
 )
 
 //
@@ -26,17 +29,44 @@ type SyncOptions struct {
 	SrcRef           string
 	SrcAuth          string
 	SrcSkipTLSVerify bool
+	SrcProxy         *util.Proxy
 	//
 	TrgtRef           string
 	TrgtAuth          string
 	TrgtSkipTLSVerify bool
+	TrgtProxy         *util.Proxy
 	//
-	Tags     *tags.TagSet
-	Platform string
-	Verbose  bool
+	Tags           *tags.TagSet
+	TagCache       *registry.TagCache
+	DateCache      *registry.DateCache
+	MaxTags        int
+	TagTransform   *tags.Transform
+	Aliases        []string
+	Platform       string
+	Platforms      []string
+	Overwrite      string
+	WithSignatures bool
+	WithReferrers  bool
+	Selector       *Selector
+	Limits         *Limits
+	Verify         *verify.Policy
+	Pins           *Pins
+	Verbose        bool
+}
+
+// Refs returns the source and target references for syncing tag, as done by
+// util.JoinRefsAndTag, with the tag transformation applied to the target.
+func (o *SyncOptions) Refs(tag string) (src, trgt string, err error) {
+	trgtTag, err := o.TagTransform.Apply(tag)
+	if err != nil {
+		return "", "", err
+	}
+	src, _ = util.JoinRefsAndTag(o.SrcRef, "", tag)
+	_, trgt = util.JoinRefsAndTag("", o.TrgtRef, trgtTag)
+	return src, trgt, nil
 }
 
 //
 type Support interface {
-	Platform(p string) error
+	Platform(p Platforms) error
 }
@@ -0,0 +1,69 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package relays
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+	"github.com/xelalexv/dregsy/internal/pkg/verify"
+)
+
+// VerifyTags checks the source images of the given tags against the signature
+// policy in sync options. It returns the tags that passed, pinned to their
+// verified digests, so that the relay syncs exactly what was verified. An error
+// is returned if any of the tags did not pass.
+func VerifyTags(opt *SyncOptions, tags []string) ([]string, error) {
+
+	if opt.Verify == nil {
+		return tags, nil
+	}
+
+	src := registry.NewRemote(opt.SrcAuth, opt.SrcSkipTLSVerify, opt.SrcProxy)
+
+	ret := make([]string, 0, len(tags))
+	failed := 0
+
+	for _, t := range tags {
+
+		ref, _ := util.JoinRefsAndTag(opt.SrcRef, "", t)
+		digest, err := opt.Verify.Verify(src, opt.SrcRef, ref)
+
+		if err != nil {
+			failed++
+			logger := log.WithField("ref", ref)
+			if verify.IsPolicyError(err) {
+				logger = logger.WithField("policy", "signature")
+			}
+			logger.Error(err)
+			continue
+		}
+
+		name, _ := util.SplitTag(t)
+		ret = append(ret, util.JoinTag(name, digest))
+	}
+
+	if failed > 0 {
+		return ret, fmt.Errorf(
+			"%d of %d images failed signature verification", failed, len(tags))
+	}
+
+	return ret, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
 	"github.com/xelalexv/dregsy/internal/pkg/relays"
 	"github.com/xelalexv/dregsy/internal/pkg/relays/docker"
 	"github.com/xelalexv/dregsy/internal/pkg/relays/skopeo"
@@ -132,6 +133,9 @@ Note: Automatic restart after config file change is currently off by default. Yo
 		return err
 	}
 
+	// repo lists are shared among the tasks of this config only
+	registry.ResetSharedRepoLists()
+
 	for _, t := range c.Tasks {
 		if err := t.validate(); err != nil {
 			return err
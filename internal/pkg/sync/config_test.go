@@ -58,14 +58,27 @@ func TestInvalidSyncConfigs(t *testing.T) {
 		"source registry in task 'test' invalid: location is nil")
 	tryConfig(th, "config/task-no-target.yaml",
 		"target registry in task 'test' invalid: location is nil")
+	tryConfig(th, "config/task-bad-max-refs.yaml",
+		"'max-refs' and 'max-tags' must not be negative")
 
 	// source & target locations
 	tryConfig(th, "config/source-no-registry.yaml",
 		"source registry in task 'test' invalid: registry not set")
 	tryConfig(th, "config/source-not-ecr.yaml", "is not an ECR registry")
+	tryConfig(th, "config/source-bad-proxy.yaml",
+		"scheme must be http, https, or socks5")
 
 	// mappings
 	tryConfig(th, "config/mapping-no-from.yaml", "mapping without 'From' path")
+	tryConfig(th, "config/mapping-bad-verify.yaml",
+		"invalid 'verify' policy: requires either 'key'")
+	tryConfig(th, "config/mapping-bad-platforms.yaml",
+		"invalid 'platform': 'all' cannot be part of a platform list")
+	tryConfig(th, "config/mapping-bad-tag-transform.yaml",
+		"invalid 'tag-transform': replacement expression missing")
+	tryConfig(th, "config/mapping-bad-overwrite.yaml",
+		"invalid overwrite mode 'sometimes'")
+	tryConfig(th, "config/mapping-bad-aliases.yaml", "invalid alias 'patch'")
 }
 
 //
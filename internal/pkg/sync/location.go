@@ -26,6 +26,7 @@ import (
 
 	"github.com/xelalexv/dregsy/internal/pkg/auth"
 	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
 //
@@ -36,6 +37,8 @@ type Location struct {
 	AuthRefresh   *time.Duration    `yaml:"auth-refresh"`
 	ListerConfig  map[string]string `yaml:"lister"`
 	ListerType    registry.ListSourceType
+	Proxy         string `yaml:"proxy"`
+	NoProxy       string `yaml:"no-proxy"`
 	//
 	ecr     bool
 	public  bool
@@ -43,6 +46,7 @@ type Location struct {
 	account string
 	//
 	creds *auth.Credentials
+	proxy *util.Proxy
 }
 
 //
@@ -67,6 +71,12 @@ func (l *Location) validate() error {
 		}
 	}
 
+	proxy, err := util.NewProxy(l.Proxy, l.NoProxy)
+	if err != nil {
+		return err
+	}
+	l.proxy = proxy
+
 	disableAuth := l.Auth == "none"
 	if disableAuth {
 		l.Auth = ""
@@ -149,6 +159,11 @@ func (l *Location) RefreshAuth() error {
 	return l.creds.Refresh()
 }
 
+//
+func (l *Location) GetProxy() *util.Proxy {
+	return l.proxy
+}
+
 //
 func (l *Location) IsECR() (bool, bool) {
 	return l.ecr, l.public
@@ -0,0 +1,144 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package sync
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/xelalexv/dregsy/internal/pkg/relays"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// Lock is the content of a lockfile. It records for each task the exact source
+// images from which target references were synced.
+type Lock struct {
+	Tasks []*LockedTask `yaml:"tasks"`
+	//
+	file string
+}
+
+//
+type LockedTask struct {
+	Name   string        `yaml:"name"`
+	Images []*relays.Pin `yaml:"images"`
+}
+
+// LoadLock loads the lockfile file. If mustExist is false, a missing lockfile
+// yields an empty lock.
+func LoadLock(file string, mustExist bool) (*Lock, error) {
+
+	ret := &Lock{file: file}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) && !mustExist {
+			return ret, nil
+		}
+		return nil, fmt.Errorf("error loading lockfile '%s': %v", file, err)
+	}
+
+	if err = yaml.Unmarshal(data, ret); err != nil {
+		return nil, fmt.Errorf("error parsing lockfile '%s': %v", file, err)
+	}
+
+	return ret, nil
+}
+
+// Save writes the lock to its lockfile. The file is replaced atomically, so a
+// crash never leaves a partial lockfile behind.
+func (l *Lock) Save() error {
+
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("error encoding lockfile: %v", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(l.file), ".dregsy-lock-")
+	if err != nil {
+		return fmt.Errorf("error writing lockfile '%s': %v", l.file, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), l.file)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing lockfile '%s': %v", l.file, err)
+	}
+
+	return nil
+}
+
+// setTask replaces the images recorded for task with name.
+func (l *Lock) setTask(name string, images []*relays.Pin) {
+	for _, t := range l.Tasks {
+		if t.Name == name {
+			t.Images = images
+			return
+		}
+	}
+	l.Tasks = append(l.Tasks, &LockedTask{Name: name, Images: images})
+}
+
+// tags returns the tags to sync from src to trgt for task with name, pinned to
+// the recorded digests.
+func (l *Lock) tags(name, src, trgt string) []string {
+
+	var ret []string
+
+	for _, t := range l.Tasks {
+		if t.Name != name {
+			continue
+		}
+		for _, img := range t.Images {
+			repo, digest := splitDigestRef(img.Source)
+			if repo != src || stripRef(img.Target) != trgt {
+				continue
+			}
+			ret = append(ret, util.JoinTag(img.Tag, digest))
+		}
+	}
+
+	return ret
+}
+
+// splitDigestRef splits a `repo@digest` reference
+func splitDigestRef(ref string) (repo, digest string) {
+	if ix := strings.LastIndex(ref, "@"); ix > -1 {
+		return ref[:ix], ref[ix+1:]
+	}
+	return ref, ""
+}
+
+// stripRef removes tag and/or digest from ref
+func stripRef(ref string) string {
+	reg, repo, _ := util.SplitRef(ref)
+	if reg == "" {
+		return repo
+	}
+	return reg + "/" + repo
+}
@@ -0,0 +1,62 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package sync
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/relays"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestLock(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	file := filepath.Join(t.TempDir(), "dregsy.lock")
+
+	_, err := LoadLock(file, true)
+	th.AssertError(err, "error loading lockfile")
+
+	lock, err := LoadLock(file, false)
+	th.AssertNoError(err)
+
+	digest := "sha256:" +
+		"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	src := "registry.acme.com/acme/app"
+	trgt := "127.0.0.1:5000/mirror/acme/app"
+
+	lock.setTask("other", []*relays.Pin{
+		{Tag: "1.0.0", Source: "a/b@" + digest, Target: "c/d:1.0.0"}})
+	lock.setTask("test", []*relays.Pin{
+		{Tag: "0.9.0", Source: src + "@" + digest, Target: trgt + ":0.9.0"}})
+	lock.setTask("test", []*relays.Pin{
+		{Tag: "1.0.0", Source: src + "@" + digest,
+			Target: trgt + ":upstream-1.0.0"},
+		{Source: src + "@" + digest, Target: trgt + "@" + digest},
+	})
+	th.AssertNoError(lock.Save())
+
+	lock, err = LoadLock(file, true)
+	th.AssertNoError(err)
+	th.AssertEqual(2, len(lock.Tasks))
+	th.AssertEqualSlices([]string{"1.0.0@" + digest, digest},
+		lock.tags("test", src, trgt))
+	th.AssertEqual(0, len(lock.tags("test", src, "127.0.0.1:5000/other")))
+	th.AssertEqual(0, len(lock.tags("unknown", src, trgt)))
+}
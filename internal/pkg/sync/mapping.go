@@ -21,8 +21,12 @@ import (
 	"regexp"
 	"strings"
 
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/relays"
 	"github.com/xelalexv/dregsy/internal/pkg/tags"
 	"github.com/xelalexv/dregsy/internal/pkg/util"
+	"github.com/xelalexv/dregsy/internal/pkg/verify"
 )
 
 //
@@ -30,15 +34,28 @@ const RegexpPrefix = "regex:"
 
 //
 type Mapping struct {
-	From     string   `yaml:"from"`
-	To       string   `yaml:"to"`
-	Tags     []string `yaml:"tags"`
-	Platform string   `yaml:"platform"`
+	From           string            `yaml:"from"`
+	To             string            `yaml:"to"`
+	Tags           []string          `yaml:"tags"`
+	TagTransform   *tags.Transform   `yaml:"tag-transform"`
+	Aliases        []string          `yaml:"aliases"`
+	Platform       relays.Platforms  `yaml:"platform"`
+	Overwrite      string            `yaml:"overwrite"`
+	Labels         map[string]string `yaml:"labels"`
+	Annotations    map[string]string `yaml:"annotations"`
+	MaxImageSize   util.Size         `yaml:"max-image-size"`
+	MaxLayers      int               `yaml:"max-layers"`
+	MaxRefs        int               `yaml:"max-refs"`
+	MaxTags        int               `yaml:"max-tags"`
+	WithSignatures bool              `yaml:"with-signatures"`
+	WithReferrers  bool              `yaml:"with-referrers"`
+	Verify         *verify.Policy    `yaml:"verify"`
 	//
 	fromFilter *regexp.Regexp
 	toFilter   *regexp.Regexp
 	toReplace  string
 	tagSet     *tags.TagSet
+	selector   *relays.Selector
 }
 
 //
@@ -80,6 +97,53 @@ func (m *Mapping) validate() error {
 		m.To = normalizePath(m.To)
 	}
 
+	if err := m.TagTransform.Validate(); err != nil {
+		return fmt.Errorf("invalid 'tag-transform': %v", err)
+	}
+
+	if err := tags.ValidateAliases(m.Aliases); err != nil {
+		return err
+	}
+
+	if err := relays.ValidateOverwrite(m.Overwrite); err != nil {
+		return err
+	}
+
+	if m.MaxImageSize < 0 || m.MaxLayers < 0 {
+		return fmt.Errorf(
+			"'max-image-size' and 'max-layers' must not be negative")
+	}
+
+	if m.MaxRefs < 0 || m.MaxTags < 0 {
+		return fmt.Errorf("'max-refs' and 'max-tags' must not be negative")
+	}
+
+	var err error
+	if m.selector, err = relays.NewSelector(
+		m.Labels, m.Annotations); err != nil {
+		return err
+	}
+
+	if err := m.Verify.Validate(); err != nil {
+		return fmt.Errorf("invalid 'verify' policy: %v", err)
+	}
+
+	if err := m.Platform.Validate(); err != nil {
+		return fmt.Errorf("invalid 'platform': %v", err)
+	}
+
+	if m.WithSignatures || m.WithReferrers {
+		if m.Platform.IsList() {
+			log.WithField("from", m.From).Warn(
+				"syncing signatures and/or referrers with a platform list, " +
+					"they will not match the trimmed target images")
+		} else if m.Platform.Single() != "all" {
+			log.WithField("from", m.From).Warn(
+				"syncing signatures and/or referrers without 'platform: all', " +
+					"they may not match target images")
+		}
+	}
+
 	if tags, err := tags.NewTagSet(m.Tags); err != nil {
 		return fmt.Errorf("'tags' uses invalid format: %v", err)
 	} else {
@@ -105,6 +169,16 @@ func (m *Mapping) filterRepos(repos []string) []string {
 	return repos
 }
 
+// literalPrefix returns the literal prefix all repositories matched by a regex
+// `from` need to start with, or an empty string if there is no such prefix
+func (m *Mapping) literalPrefix() string {
+	if !m.isRegexpFrom() || m.fromFilter == nil {
+		return ""
+	}
+	prefix, _ := m.fromFilter.LiteralPrefix()
+	return prefix
+}
+
 //
 func (m *Mapping) mapPath(p string) string {
 	if m.isRegexpTo() {
@@ -119,6 +193,25 @@ func (m *Mapping) mapPath(p string) string {
 	return p
 }
 
+// maxRefs returns the limit for the number of repositories this mapping may
+// match, with the setting of the mapping taking precedence over that of task
+func (m *Mapping) maxRefs(t *Task) int {
+	if m.MaxRefs > 0 {
+		return m.MaxRefs
+	}
+	return t.MaxRefs
+}
+
+// maxTags returns the limit for the number of tags to sync per repository for
+// this mapping, with the setting of the mapping taking precedence over that of
+// task
+func (m *Mapping) maxTags(t *Task) int {
+	if m.MaxTags > 0 {
+		return m.MaxTags
+	}
+	return t.MaxTags
+}
+
 //
 func (m *Mapping) isRegexpFrom() bool {
 	return isRegexp(m.From)
@@ -25,9 +25,11 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
 	"github.com/xelalexv/dregsy/internal/pkg/relays"
 	"github.com/xelalexv/dregsy/internal/pkg/relays/docker"
 	"github.com/xelalexv/dregsy/internal/pkg/relays/skopeo"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
 	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
@@ -43,6 +45,8 @@ type Sync struct {
 	relay    Relay
 	shutdown chan bool
 	ticks    chan bool
+	lock     *Lock
+	locked   bool
 }
 
 //
@@ -82,6 +86,19 @@ func New(conf *SyncConfig) (*Sync, error) {
 	return sync, nil
 }
 
+// UseLock sets the lockfile to use. If locked is false, the source digests of
+// all synced images are recorded in the lockfile. Otherwise, exactly the images
+// recorded in the lockfile are synced, instead of resolving tags.
+func (s *Sync) UseLock(file string, locked bool) error {
+	lock, err := LoadLock(file, locked)
+	if err != nil {
+		return err
+	}
+	s.lock = lock
+	s.locked = locked
+	return nil
+}
+
 //
 func (s *Sync) Shutdown() {
 	s.shutdown <- true
@@ -215,6 +232,11 @@ func (s *Sync) SyncFromConfig(conf *SyncConfig, taskFilter string) (bool, error)
 		errs = errs || t.failed
 	}
 
+	if hits, misses := registry.SharedRepoListStats(); misses > 0 {
+		log.WithFields(log.Fields{"shared": hits, "created": misses}).Info(
+			"repository lists used by tasks")
+	}
+
 	if errs {
 		return restart, fmt.Errorf(
 			"one or more tasks had errors, please see log for details")
@@ -238,6 +260,11 @@ func (s *Sync) syncTask(t *Task) {
 		"target": t.Target.Registry}).Info("syncing task")
 	t.failed = false
 
+	var pins []*relays.Pin
+	budget := relays.NewBudget(t.MaxBytesPerRun)
+	tagCache := registry.NewTagCache()
+	dateCache := registry.NewDateCache()
+
 	for _, m := range t.Mappings {
 
 		log.WithFields(log.Fields{"from": m.From, "to": m.To}).Info("mapping")
@@ -253,6 +280,12 @@ func (s *Sync) syncTask(t *Task) {
 			continue
 		}
 
+		limits := &relays.Limits{
+			MaxImageSize: m.MaxImageSize,
+			MaxLayers:    m.MaxLayers,
+			Budget:       budget,
+		}
+
 		refs, err := t.mappingRefs(m)
 		if err != nil {
 			log.Error(err)
@@ -271,16 +304,71 @@ func (s *Sync) syncTask(t *Task) {
 				break
 			}
 
-			if err := s.relay.Sync(&relays.SyncOptions{
+			tagSet, err := t.tagSet(m, src)
+			if err != nil {
+				log.Errorf("invalid tags listed for '%s': %v", src, err)
+				t.fail(true)
+				continue
+			}
+
+			opt := &relays.SyncOptions{
 				SrcRef:            src,
 				SrcAuth:           t.Source.GetAuth(),
 				SrcSkipTLSVerify:  t.Source.SkipTLSVerify,
+				SrcProxy:          t.Source.GetProxy(),
 				TrgtRef:           trgt,
 				TrgtAuth:          t.Target.GetAuth(),
 				TrgtSkipTLSVerify: t.Target.SkipTLSVerify,
-				Tags:              m.tagSet,
-				Platform:          m.Platform,
-				Verbose:           t.Verbose}); err != nil {
+				TrgtProxy:         t.Target.GetProxy(),
+				Tags:              tagSet,
+				TagCache:          tagCache,
+				DateCache:         dateCache,
+				MaxTags:           m.maxTags(t),
+				TagTransform:      m.TagTransform,
+				Aliases:           m.Aliases,
+				Platform:          m.Platform.Single(),
+				Platforms:         m.Platform.List(),
+				Overwrite:         m.Overwrite,
+				Selector:          m.selector,
+				Limits:            limits,
+				WithSignatures:    m.WithSignatures,
+				WithReferrers:     m.WithReferrers,
+				Verify:            m.Verify,
+				Verbose:           t.Verbose}
+
+			if s.locked {
+				locked := s.lock.tags(t.Name, src, trgt)
+				if len(locked) == 0 {
+					log.WithField("ref", src).Warn(
+						"no images recorded in lockfile, skipping")
+					continue
+				}
+				if opt.Tags, err = tags.NewTagSet(locked); err != nil {
+					log.Errorf("invalid tags in lockfile: %v", err)
+					t.fail(true)
+					continue
+				}
+			} else if s.lock != nil {
+				opt.Pins = &relays.Pins{}
+			}
+
+			if err := s.relay.Sync(opt); err != nil {
+				log.Error(err)
+				t.fail(true)
+			} else {
+				pins = append(pins, opt.Pins.List()...)
+			}
+		}
+	}
+
+	// a failed task could leave an incomplete record, so we keep what we have
+	if s.lock != nil && !s.locked {
+		if t.failed {
+			log.WithField("task", t.Name).Warn(
+				"task had errors, not updating lockfile")
+		} else {
+			s.lock.setTask(t.Name, pins)
+			if err := s.lock.Save(); err != nil {
 				log.Error(err)
 				t.fail(true)
 			}
@@ -17,8 +17,12 @@
 package sync
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/xelalexv/dregsy/internal/pkg/relays"
 	"github.com/xelalexv/dregsy/internal/pkg/test"
 )
 
@@ -30,6 +34,72 @@ func TestInvalidSync(t *testing.T) {
 	// mappings
 	trySync(th, "config/docker-platform-all.yaml",
 		"relay 'docker' does not support mappings with 'platform: all'")
+	trySync(th, "config/docker-platform-list.yaml",
+		"relay 'docker' does not support mappings with a list of platforms")
+}
+
+//
+func TestMaxRefs(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	reg := th.NewRegistry(false)
+	img := th.RandomImage(nil)
+	for _, r := range []string{"acme/a", "acme/b", "acme/c", "other/d"} {
+		th.PushImage(reg+"/"+r+":1.0", img)
+	}
+
+	// the in-process registry does not sort its catalog, which the lister
+	// needs to detect when using the literal prefix of 'from'
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	th.AssertNoError(os.WriteFile(file, []byte(fmt.Sprintf(`relay: skopeo
+tasks:
+- name: capped
+  source:
+    registry: %s
+  target:
+    registry: 127.0.0.1:5000
+  mappings:
+  - from: regex:acme/.*
+    max-refs: 2
+`, reg)), 0644))
+
+	c, err := LoadConfig(file)
+	th.AssertNoError(err)
+	task := c.Tasks[0]
+
+	_, err = task.mappingRefs(task.Mappings[0])
+	th.AssertError(err,
+		"mapping matches 3 repositories, exceeding 'max-refs' limit of 2")
+
+	relay := &recordingRelay{}
+	s := &Sync{relay: relay}
+	s.syncTask(task)
+	th.AssertTrue(task.failed)
+	th.AssertEqual(0, len(relay.synced))
+
+	task.Mappings[0].MaxRefs = 3
+	task.failed = false
+	s.syncTask(task)
+	th.AssertFalse(task.failed)
+	th.AssertEqual(3, len(relay.synced))
+}
+
+//
+type recordingRelay struct {
+	synced []string
+}
+
+//
+func (r *recordingRelay) Prepare() error { return nil }
+
+//
+func (r *recordingRelay) Dispose() error { return nil }
+
+//
+func (r *recordingRelay) Sync(opt *relays.SyncOptions) error {
+	r.synced = append(r.synced, opt.SrcRef)
+	return nil
 }
 
 //
@@ -19,21 +19,27 @@ package sync
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/tags"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
 )
 
 //
 type Task struct {
-	Name     string     `yaml:"name"`
-	Interval int        `yaml:"interval"`
-	Source   *Location  `yaml:"source"`
-	Target   *Location  `yaml:"target"`
-	Mappings []*Mapping `yaml:"mappings"`
-	Verbose  bool       `yaml:"verbose"`
+	Name           string     `yaml:"name"`
+	Interval       int        `yaml:"interval"`
+	Source         *Location  `yaml:"source"`
+	Target         *Location  `yaml:"target"`
+	Mappings       []*Mapping `yaml:"mappings"`
+	Verbose        bool       `yaml:"verbose"`
+	MaxBytesPerRun util.Size  `yaml:"max-bytes-per-run"`
+	MaxRefs        int        `yaml:"max-refs"`
+	MaxTags        int        `yaml:"max-tags"`
 	//
 	repoList *registry.RepoList
 	ticker   *time.Ticker
@@ -60,6 +66,14 @@ func (t *Task) validate() error {
 		return errors.New("task interval needs to be 0 or a positive integer")
 	}
 
+	if t.MaxBytesPerRun < 0 {
+		return errors.New("'max-bytes-per-run' must not be negative")
+	}
+
+	if t.MaxRefs < 0 || t.MaxTags < 0 {
+		return errors.New("'max-refs' and 'max-tags' must not be negative")
+	}
+
 	if err := t.Source.validate(); err != nil {
 		return fmt.Errorf(
 			"source registry in task '%s' invalid: %v", t.Name, err)
@@ -71,18 +85,26 @@ func (t *Task) validate() error {
 	}
 
 	hasRegexp := false
+	var prefixes []string
 	for _, m := range t.Mappings {
 		if err := m.validate(); err != nil {
 			return err
 		}
-		hasRegexp = hasRegexp || m.isRegexpFrom()
+		if m.isRegexpFrom() {
+			hasRegexp = true
+			prefixes = append(prefixes, m.literalPrefix())
+		}
 	}
 
 	if hasRegexp {
 		var err error
 		s := t.Source
-		if t.repoList, err = registry.NewRepoList(s.Registry, s.SkipTLSVerify,
-			s.ListerType, s.ListerConfig, s.creds); err != nil {
+		// all mappings share the repo list, so we can only use a prefix
+		// common to all of them; tasks with the same source, lister settings
+		// and prefix share their repo list as well
+		if t.repoList, err = registry.SharedRepoList(s.Registry,
+			s.SkipTLSVerify, s.ListerType, s.ListerConfig, s.creds, s.proxy,
+			commonPrefix(prefixes)); err != nil {
 			return fmt.Errorf(
 				"cannot create repo list for task '%s': %v", t.Name, err)
 		}
@@ -178,11 +200,31 @@ func (t *Task) mappingRefs(m *Mapping) ([][2]string, error) {
 				t.Target.Registry + m.mapPath(m.From),
 			})
 		}
+
+		if max := m.maxRefs(t); max > 0 && len(ret) > max {
+			return nil, fmt.Errorf(
+				"mapping matches %d repositories, exceeding 'max-refs' limit "+
+					"of %d, aborting", len(ret), max)
+		}
 	}
 
 	return ret, nil
 }
 
+// tagSet returns the tag set to use for syncing source reference src of mapping
+// m. If the task's lister provides tags for the repository, these take the
+// place of the mapping's tags.
+func (t *Task) tagSet(m *Mapping, src string) (*tags.TagSet, error) {
+	if t.repoList != nil && m.isRegexpFrom() {
+		repo := strings.TrimPrefix(src, t.Source.Registry)
+		if listed := t.repoList.Tags(repo); len(listed) > 0 {
+			log.WithField("ref", src).Debugf("using listed tags: %v", listed)
+			return tags.NewTagSet(listed)
+		}
+	}
+	return m.tagSet, nil
+}
+
 //
 func (t *Task) ensureTargetExists(ref string) error {
 	log.WithField("ref", ref).Debug("ensuring target exists")
@@ -191,3 +233,17 @@ func (t *Task) ensureTargetExists(ref string) error {
 	}
 	return nil
 }
+
+// commonPrefix returns the longest common prefix of all strings in s
+func commonPrefix(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	ret := s[0]
+	for _, p := range s[1:] {
+		for !strings.HasPrefix(p, ret) {
+			ret = ret[:len(ret)-1]
+		}
+	}
+	return ret
+}
@@ -0,0 +1,121 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package tags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// dates looks up and remembers the creation dates of tags during expansion
+type dates struct {
+	created func(tag string) (time.Time, error)
+	dates   map[string]time.Time
+}
+
+//
+func newDates(created func(tag string) (time.Time, error)) *dates {
+	return &dates{created: created, dates: make(map[string]time.Time)}
+}
+
+// get returns the creation date of tag. If it cannot be determined, a zero time
+// is returned, i.e. the image is considered to be very old.
+func (d *dates) get(tag string) time.Time {
+
+	if t, ok := d.dates[tag]; ok {
+		return t
+	}
+
+	var t time.Time
+	if d.created == nil {
+		log.WithField("tag", tag).Warn(
+			"creation dates not available, treating image as old")
+	} else {
+		var err error
+		if t, err = d.created(tag); err != nil {
+			log.WithField("tag", tag).Warnf(
+				"cannot get creation date, treating image as old: %v", err)
+		}
+	}
+
+	d.dates[tag] = t
+	return t
+}
+
+// filterNewerThan returns the tags of images created within age from now
+func filterNewerThan(tags []string, age time.Duration, d *dates) []string {
+
+	limit := time.Now().Add(-age)
+	ret := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+		if d.get(t).After(limit) {
+			ret = append(ret, t)
+		} else {
+			log.WithField("tag", t).Debug("pruning tag, image too old")
+		}
+	}
+
+	return ret
+}
+
+// reduceByDate returns the limit tags with the newest images, sorted by name
+func reduceByDate(tags []string, limit int, d *dates) []string {
+
+	if len(tags) > limit {
+		sort.SliceStable(tags, func(i, j int) bool {
+			return d.get(tags[i]).After(d.get(tags[j])) // descending
+		})
+		log.Debugf("removed tags: %v", tags[limit:])
+		tags = tags[:limit]
+	}
+
+	sort.Strings(tags)
+	return tags
+}
+
+// parseAge parses an age such as `90d`, `2w`, or anything understood by
+// time.ParseDuration
+func parseAge(age string) (time.Duration, error) {
+
+	age = strings.TrimSpace(age)
+	var unit time.Duration
+
+	switch {
+	case strings.HasSuffix(age, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(age, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(age)
+		if err == nil && d <= 0 {
+			err = fmt.Errorf("age must be positive")
+		}
+		return d, err
+	}
+
+	n, err := strconv.Atoi(age[:len(age)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid age '%s'", age)
+	}
+	return time.Duration(n) * unit, nil
+}
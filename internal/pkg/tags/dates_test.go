@@ -0,0 +1,87 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package tags
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestExpandByDate(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	now := time.Now()
+	ages := map[string]time.Duration{
+		"nightly-a": 100 * 24 * time.Hour,
+		"nightly-b": 10 * 24 * time.Hour,
+		"nightly-c": 2 * 24 * time.Hour,
+		"abc1234":   5 * time.Hour,
+	}
+
+	lister := func() ([]string, error) {
+		return []string{"nightly-a", "nightly-b", "nightly-c", "abc1234",
+			"broken"}, nil
+	}
+
+	fetched := make(map[string]int)
+	created := func(tag string) (time.Time, error) {
+		fetched[tag]++
+		if age, ok := ages[tag]; ok {
+			return now.Add(-age), nil
+		}
+		return time.Time{}, fmt.Errorf("no such image")
+	}
+
+	for _, tc := range []struct {
+		tags     []string
+		expected []string
+	}{
+		{[]string{"newer-than: 30d"},
+			[]string{"abc1234", "nightly-b", "nightly-c"}},
+		{[]string{"newer-than: 1w", "regex: nightly-.*"},
+			[]string{"nightly-c"}},
+		{[]string{"newer-than: 12h", "regex: [a-z]+[0-9]+", "nightly-a"},
+			[]string{"abc1234", "nightly-a"}},
+		{[]string{"keep: newest 2"}, []string{"abc1234", "nightly-c"}},
+		{[]string{"regex: nightly-.*", "keep: newest 2"},
+			[]string{"nightly-b", "nightly-c"}},
+		{[]string{"newer-than: 30d", "keep: newest 1"}, []string{"abc1234"}},
+	} {
+		ts, err := NewTagSet(tc.tags)
+		th.AssertNoError(err)
+		th.AssertTrue(ts.NeedsDates())
+		fetched = make(map[string]int)
+		tags, err := ts.Expand(lister, created)
+		th.AssertNoError(err)
+		th.AssertEqualSlices(tc.expected, tags)
+		for _, n := range fetched {
+			th.AssertEqual(1, n)
+		}
+	}
+
+	_, err := NewTagSet([]string{"keep: latest 2", "keep: newest 2"})
+	th.AssertError(err, "cannot be combined")
+	_, err = NewTagSet([]string{"newer-than: soon"})
+	th.AssertError(err, "invalid 'newer-than: soon'")
+	_, err = NewTagSet([]string{"newer-than: -5h"})
+	th.AssertError(err, "age must be positive")
+}
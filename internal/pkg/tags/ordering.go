@@ -0,0 +1,223 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package tags
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// ordering determines which tags are the latest ones for `keep: latest n`
+type ordering interface {
+	// key returns the sort key for tag, and false if tag does not follow the
+	// ordering
+	key(tag string) (sortKey, bool)
+}
+
+// sortKey is the key by which tags are sorted
+type sortKey interface {
+	// less returns true if the tag of this key is older than the tag of other
+	less(other sortKey) bool
+}
+
+// calendar versions like `2024.03.1` or `24.04`, tolerating suffixes
+var calver = regexp.MustCompile(
+	`^v?([0-9]{4}|[0-9]{2})(\.[0-9]+)+([-+_].*)?$`)
+
+// build numbers
+var number = regexp.MustCompile(`^[0-9]+$`)
+
+// newOrdering creates the ordering described by spec, i.e. the part following
+// `by` in `keep: latest n by {spec}`
+func newOrdering(spec string) (ordering, error) {
+
+	spec = strings.TrimSpace(spec)
+	kind := spec
+	var arg string
+	if ix := strings.IndexAny(spec, " \t"); ix > -1 {
+		kind = spec[:ix]
+		arg = strings.TrimSpace(spec[ix:])
+	}
+
+	switch kind {
+
+	case "semver":
+		if arg == "" {
+			return &semverOrdering{}, nil
+		}
+
+	case "calver":
+		if arg == "" {
+			return &regexOrdering{expr: calver}, nil
+		}
+
+	case "number":
+		if arg == "" {
+			return &regexOrdering{expr: number}, nil
+		}
+
+	case "date":
+		if arg == "" {
+			return nil, fmt.Errorf("ordering by date requires a time layout")
+		}
+		return &dateOrdering{layout: arg}, nil
+
+	case "regex":
+		if arg == "" {
+			return nil, fmt.Errorf("ordering by regex requires an expression")
+		}
+		expr, err := util.CompileRegex(arg, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ordering regex: %v", err)
+		}
+		if expr.NumSubexp() > 1 {
+			return nil, fmt.Errorf(
+				"ordering regex must have at most one capture group")
+		}
+		return &regexOrdering{expr: expr}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown ordering '%s'", kind)
+	}
+
+	return nil, fmt.Errorf("ordering '%s' takes no argument", kind)
+}
+
+// semverOrdering orders tags by semver, optionally ranking all pre-releases
+// below stable releases
+type semverOrdering struct {
+	preferStable bool
+}
+
+//
+func (o *semverOrdering) key(tag string) (sortKey, bool) {
+	v, err := semver.ParseTolerant(tag)
+	return &semverKey{Version: v, preferStable: o.preferStable}, err == nil
+}
+
+//
+type semverKey struct {
+	semver.Version
+	preferStable bool
+}
+
+//
+func (k *semverKey) less(other sortKey) bool {
+	o := other.(*semverKey)
+	if stable, otherStable := len(k.Pre) == 0, len(o.Pre) == 0; k.preferStable &&
+		stable != otherStable {
+		return otherStable
+	}
+	if c := k.Compare(o.Version); c != 0 {
+		return c < 0
+	}
+	// semver ignores build metadata, but we need a stable order
+	return strings.Join(k.Build, ".") < strings.Join(o.Build, ".")
+}
+
+// dateOrdering orders tags containing a date stamp, using a Go time layout
+// that needs to match the complete tag, e.g. `nightly-20060102`
+type dateOrdering struct {
+	layout string
+}
+
+//
+func (o *dateOrdering) key(tag string) (sortKey, bool) {
+	t, err := time.Parse(o.layout, tag)
+	return dateKey(t), err == nil
+}
+
+//
+type dateKey time.Time
+
+//
+func (k dateKey) less(other sortKey) bool {
+	return time.Time(k).Before(time.Time(other.(dateKey)))
+}
+
+// regexOrdering orders tags matching expr by their natural order. If expr has
+// a capture group, only the captured part is considered.
+type regexOrdering struct {
+	expr *regexp.Regexp
+}
+
+//
+func (o *regexOrdering) key(tag string) (sortKey, bool) {
+	m := o.expr.FindStringSubmatch(tag)
+	if m == nil {
+		return nil, false
+	}
+	if len(m) > 1 && o.expr.NumSubexp() == 1 {
+		return naturalKey(m[1]), true
+	}
+	return naturalKey(m[0]), true
+}
+
+// naturalKey compares strings such that digit sequences within them are
+// compared by their numeric value, e.g. `1.9` is less than `1.10`
+type naturalKey string
+
+//
+func (k naturalKey) less(other sortKey) bool {
+
+	a, b := string(k), string(other.(naturalKey))
+
+	for a != "" && b != "" {
+		sa, da := nextSegment(a)
+		sb, db := nextSegment(b)
+		a, b = a[len(sa):], b[len(sb):]
+		if sa == sb {
+			continue
+		}
+		if da && db {
+			// compare numerically, without risking overflow
+			na, nb := strings.TrimLeft(sa, "0"), strings.TrimLeft(sb, "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+		return sa < sb
+	}
+
+	return len(a) < len(b)
+}
+
+// nextSegment returns the leading run of either digits or non-digits in s, and
+// whether it consists of digits
+func nextSegment(s string) (string, bool) {
+	digit := isDigit(s[0])
+	ix := 1
+	for ix < len(s) && isDigit(s[ix]) == digit {
+		ix++
+	}
+	return s[:ix], digit
+}
+
+//
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,153 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package tags
+
+import (
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestKeepLatestOrdering(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	for _, tc := range []struct {
+		available []string
+		tags      []string
+		expected  []string
+	}{
+		{
+			[]string{"2023.12.4", "2024.3.1", "2024.10.0", "2024.9.2", "edge"},
+			[]string{"keep: latest 2 by calver"},
+			[]string{"2024.10.0", "2024.9.2", "edge"},
+		},
+		{
+			[]string{"98", "99", "100", "101", "latest"},
+			[]string{"regex: [0-9]+", "keep: latest 3 by number"},
+			[]string{"100", "101", "99"},
+		},
+		{
+			[]string{"nightly-20240131", "nightly-20240201", "nightly-20231231"},
+			[]string{"keep: latest 2 by date nightly-20060102"},
+			[]string{"nightly-20240131", "nightly-20240201"},
+		},
+		{
+			[]string{"build-9-abc", "build-10-def", "build-11-aaa", "stable"},
+			[]string{"keep: latest 2 by regex build-([0-9]+)-.+"},
+			[]string{"build-10-def", "build-11-aaa", "stable"},
+		},
+		{
+			[]string{"1.9.0", "1.10.0", "1.2.0"},
+			[]string{"keep: latest 1 by semver"},
+			[]string{"1.10.0"},
+		},
+	} {
+		ts, err := NewTagSet(tc.tags)
+		th.AssertNoError(err)
+		tags, err := ts.Expand(func() ([]string, error) {
+			return append([]string{}, tc.available...), nil
+		}, nil)
+		th.AssertNoError(err)
+		th.AssertEqualSlices(tc.expected, tags)
+	}
+
+	for tags, msg := range map[string]string{
+		"keep: latest 2 by color":         "unknown ordering 'color'",
+		"keep: latest 2 by date":          "requires a time layout",
+		"keep: latest 2 by calver 1":      "takes no argument",
+		"keep: latest 2 by regex (a)-(b)": "at most one capture group",
+	} {
+		_, err := NewTagSet([]string{tags})
+		th.AssertError(err, msg)
+	}
+}
+
+//
+func TestKeepLatestPer(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	available := []string{"1.1.0", "1.1.1", "1.1.2", "1.2.0", "1.2.1",
+		"2.0.0", "2.0.1", "2.0.2", "2.1.0", "latest"}
+	lister := func() ([]string, error) {
+		return append([]string{}, available...), nil
+	}
+
+	for _, tc := range []struct {
+		tags     []string
+		expected []string
+	}{
+		{[]string{"keep: latest 2 per minor"}, []string{"1.1.1", "1.1.2",
+			"1.2.0", "1.2.1", "2.0.1", "2.0.2", "2.1.0", "latest"}},
+		{[]string{"keep: latest 1 per major by semver"},
+			[]string{"1.2.1", "2.1.0", "latest"}},
+		{[]string{"semver: <2.0.0", "keep: latest 1 per minor"},
+			[]string{"1.1.2", "1.2.1"}},
+	} {
+		ts, err := NewTagSet(tc.tags)
+		th.AssertNoError(err)
+		tags, err := ts.Expand(lister, nil)
+		th.AssertNoError(err)
+		th.AssertEqualSlices(tc.expected, tags)
+	}
+
+	_, err := NewTagSet([]string{"keep: latest 2 per patch"})
+	th.AssertError(err, "can only keep per 'major' or 'minor'")
+	_, err = NewTagSet([]string{"keep: latest 2 per minor by calver"})
+	th.AssertError(err, "'per' requires semver ordering")
+}
+
+//
+func TestSemverPolicies(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	available := []string{"1.9.0", "1.9.1", "2.0.0-rc.1", "2.0.0-rc.2",
+		"1.9.1+ubuntu", "1.9.1+debian", "1.8.0+ubuntu"}
+	lister := func() ([]string, error) {
+		return append([]string{}, available...), nil
+	}
+
+	for _, tc := range []struct {
+		tags     []string
+		expected []string
+	}{
+		{[]string{"semver: >=1.9.0", "prereleases: exclude"},
+			[]string{"1.9.0", "1.9.1", "1.9.1+debian", "1.9.1+ubuntu"}},
+		{[]string{"semver: >=1.0.0", "keep: latest 2"},
+			[]string{"2.0.0-rc.1", "2.0.0-rc.2"}},
+		{[]string{"semver: >=1.0.0", "keep: latest 2",
+			"prereleases: prefer-stable"},
+			[]string{"1.9.1+debian", "1.9.1+ubuntu"}},
+		{[]string{"semver: >=1.0.0", "keep: latest 2",
+			"prereleases: prefer-stable", "build-metadata: equivalent"},
+			[]string{"1.9.0", "1.9.1", "1.9.1+debian", "1.9.1+ubuntu"}},
+	} {
+		ts, err := NewTagSet(tc.tags)
+		th.AssertNoError(err)
+		tags, err := ts.Expand(lister, nil)
+		th.AssertNoError(err)
+		th.AssertEqualSlices(tc.expected, tags)
+	}
+
+	_, err := NewTagSet([]string{"prereleases: maybe"})
+	th.AssertError(err, "invalid 'prereleases: maybe'")
+	_, err = NewTagSet([]string{"build-metadata: ignore"})
+	th.AssertError(err, "invalid 'build-metadata: ignore'")
+}
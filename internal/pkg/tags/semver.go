@@ -17,7 +17,10 @@
 package tags
 
 import (
+	"fmt"
+	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/blang/semver/v4"
 )
@@ -50,3 +53,74 @@ type version struct {
 	semver.Version
 	tag string
 }
+
+//
+const AliasMajor = "major"
+const AliasMinor = "minor"
+const AliasLatest = "latest"
+
+// release versions eligible as alias targets
+var release = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// ValidateAliases checks whether all kinds are valid alias kinds
+func ValidateAliases(kinds []string) error {
+	for _, k := range kinds {
+		if k != AliasMajor && k != AliasMinor && k != AliasLatest {
+			return fmt.Errorf(
+				"invalid alias '%s', must be one of '%s', '%s', or '%s'",
+				k, AliasMajor, AliasMinor, AliasLatest)
+		}
+	}
+	return nil
+}
+
+// Aliases derives floating alias tags of the given kinds from the semver tags
+// among tags, and returns them mapped to the tag they point to. For example,
+// with tags `1.3.2`, `1.4.0`, and `1.4.1`, alias `1` as well as `latest` point
+// to `1.4.1`, alias `1.3` to `1.3.2`, and `1.4` to `1.4.1`. A `v` prefix is
+// kept for major & minor aliases. Only tags with a full major.minor.patch
+// version are considered, so pre-releases, tags with suffixes or digest, and
+// existing aliases are ignored.
+func Aliases(tags []string, kinds []string) map[string]string {
+
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	vers := make(versions, 0, len(tags))
+	for _, t := range tags {
+		if !release.MatchString(t) {
+			continue
+		}
+		if v, err := semver.ParseTolerant(t); err == nil {
+			vers = append(vers, &version{Version: v, tag: t})
+		}
+	}
+	vers.sort() // descending, semver
+
+	ret := make(map[string]string)
+	set := func(alias, tag string) {
+		if _, ok := ret[alias]; !ok { // first one is newest
+			ret[alias] = tag
+		}
+	}
+
+	for _, v := range vers {
+		prefix := ""
+		if strings.HasPrefix(v.tag, "v") {
+			prefix = "v"
+		}
+		for _, k := range kinds {
+			switch k {
+			case AliasMajor:
+				set(fmt.Sprintf("%s%d", prefix, v.Major), v.tag)
+			case AliasMinor:
+				set(fmt.Sprintf("%s%d.%d", prefix, v.Major, v.Minor), v.tag)
+			case AliasLatest:
+				set(AliasLatest, v.tag)
+			}
+		}
+	}
+
+	return ret
+}
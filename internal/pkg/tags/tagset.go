@@ -21,6 +21,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	log "github.com/sirupsen/logrus"
@@ -32,17 +33,40 @@ import (
 const SemverPrefix = "semver:"
 const RegexpPrefix = "regex:"
 const KeepPrefix = "keep:"
+const NewerThanPrefix = "newer-than:"
+const PrereleasesPrefix = "prereleases:"
+const BuildMetadataPrefix = "build-metadata:"
+
+//
+const PerMajor = "major"
+const PerMinor = "minor"
+
+//
+const PrereleasesInclude = "include"
+const PrereleasesExclude = "exclude"
+const PrereleasesPreferStable = "prefer-stable"
+
+//
+const BuildMetadataDistinct = "distinct"
+const BuildMetadataEquivalent = "equivalent"
 
 //
 var keepCount *util.Regex
+var keepNewest *util.Regex
 
 //
 func init() {
 	var err error
 	if keepCount, err = util.NewRegex(
-		"keep:[[:space:]]+latest[[:space:]]+[[:digit:]]+"); err != nil {
+		"keep:[[:space:]]+latest[[:space:]]+[[:digit:]]+" +
+			"([[:space:]]+per[[:space:]]+[[:alpha:]]+)?" +
+			"([[:space:]]+by[[:space:]]+.+)?"); err != nil {
 		panic(fmt.Sprintf("invalid regex for keep latest: %v", err))
 	}
+	if keepNewest, err = util.NewRegex(
+		"keep:[[:space:]]+newest[[:space:]]+[[:digit:]]+"); err != nil {
+		panic(fmt.Sprintf("invalid regex for keep newest: %v", err))
+	}
 }
 
 //
@@ -51,16 +75,27 @@ func NewTagSet(tags []string) (*TagSet, error) {
 	if err := ret.add(tags); err != nil {
 		return nil, err
 	}
+	if ret.keepCount > 0 && ret.keepNewest > 0 {
+		return nil, fmt.Errorf(
+			"'keep: latest' and 'keep: newest' cannot be combined")
+	}
 	return ret, nil
 }
 
 //
 type TagSet struct {
-	verbatim  []string
-	semver    []semver.Range
-	regex     []*util.Regex
-	keep      []*util.Regex
-	keepCount int
+	verbatim   []string
+	semver     []semver.Range
+	regex      []*util.Regex
+	keep       []*util.Regex
+	keepCount  int
+	order      ordering
+	per        string
+	keepNewest int
+	newerThan  time.Duration
+	//
+	prereleases   string
+	buildMetadata string
 }
 
 //
@@ -87,6 +122,26 @@ func (ts *TagSet) add(tags []string) error {
 				return err
 			}
 
+		case isKeepNewest(t):
+			if err := ts.setKeepNewest(t); err != nil {
+				return err
+			}
+
+		case isNewerThan(t):
+			if err := ts.setNewerThan(t); err != nil {
+				return err
+			}
+
+		case isPrereleases(t):
+			if err := ts.setPrereleases(t); err != nil {
+				return err
+			}
+
+		case isBuildMetadata(t):
+			if err := ts.setBuildMetadata(t); err != nil {
+				return err
+			}
+
 		case isKeep(t):
 			if err := ts.addKeep(t); err != nil {
 				return err
@@ -121,16 +176,76 @@ func (ts *TagSet) addRegex(r string) (err error) {
 	return
 }
 
-//
+// setKeepCount handles `keep: latest n`, optionally followed by `per {bucket}`
+// and/or `by {ordering}`
 func (ts *TagSet) setKeepCount(c string) (err error) {
-	if p := strings.Split(c, " "); len(p) > 1 {
-		ts.keepCount, err = strconv.Atoi(p[len(p)-1])
-	} else {
-		err = fmt.Errorf("invalid keep count: %s", c)
+
+	p := strings.Fields(c)
+	if len(p) < 3 {
+		return fmt.Errorf("invalid keep count: %s", c)
+	}
+	if ts.keepCount, err = strconv.Atoi(p[2]); err != nil {
+		return err
+	}
+
+	ts.per = ""
+	if len(p) > 4 && p[3] == "per" {
+		if ts.per = p[4]; ts.per != PerMajor && ts.per != PerMinor {
+			return fmt.Errorf("invalid '%s': can only keep per '%s' or '%s'",
+				c, PerMajor, PerMinor)
+		}
+	}
+
+	ts.order = nil
+	if ix := strings.Index(c, " by "); ix > -1 {
+		if ts.order, err = newOrdering(c[ix+len(" by "):]); err != nil {
+			return fmt.Errorf("invalid '%s': %v", c, err)
+		}
+		if _, ok := ts.order.(*semverOrdering); !ok && ts.per != "" {
+			return fmt.Errorf(
+				"invalid '%s': 'per' requires semver ordering", c)
+		}
 	}
 	return
 }
 
+//
+func (ts *TagSet) setKeepNewest(c string) (err error) {
+	p := strings.Fields(c)
+	ts.keepNewest, err = strconv.Atoi(p[len(p)-1])
+	return
+}
+
+//
+func (ts *TagSet) setNewerThan(n string) (err error) {
+	if ts.newerThan, err = parseAge(n[len(NewerThanPrefix):]); err != nil {
+		err = fmt.Errorf("invalid '%s': %v", n, err)
+	}
+	return
+}
+
+//
+func (ts *TagSet) setPrereleases(p string) error {
+	ts.prereleases = strings.TrimSpace(p[len(PrereleasesPrefix):])
+	switch ts.prereleases {
+	case PrereleasesInclude, PrereleasesExclude, PrereleasesPreferStable:
+		return nil
+	}
+	return fmt.Errorf("invalid '%s', must be one of '%s', '%s', or '%s'", p,
+		PrereleasesInclude, PrereleasesExclude, PrereleasesPreferStable)
+}
+
+//
+func (ts *TagSet) setBuildMetadata(b string) error {
+	ts.buildMetadata = strings.TrimSpace(b[len(BuildMetadataPrefix):])
+	switch ts.buildMetadata {
+	case BuildMetadataDistinct, BuildMetadataEquivalent:
+		return nil
+	}
+	return fmt.Errorf("invalid '%s', must be either '%s' or '%s'", b,
+		BuildMetadataDistinct, BuildMetadataEquivalent)
+}
+
 //
 func (ts *TagSet) addKeep(k string) (err error) {
 	ts.keep, err = ts.addFilter(k, KeepPrefix, ts.keep)
@@ -169,15 +284,24 @@ func (ts *TagSet) HasRegex() bool {
 	return len(ts.regex) > 0
 }
 
+// NeedsDates returns true if expansion needs the creation dates of images.
+func (ts *TagSet) NeedsDates() bool {
+	return ts.keepNewest > 0 || ts.newerThan > 0
+}
+
 //
 func (ts *TagSet) NeedsExpansion() bool {
 	return ts.IsEmpty() || ts.HasSemver() || ts.HasRegex()
 }
 
-//
-func (ts *TagSet) Expand(lister func() ([]string, error)) ([]string, error) {
+// Expand determines the tags to sync. lister is used for listing all available
+// tags where needed. created is used for getting the creation date of a tag's
+// image, if the tag set contains age based filters.
+func (ts *TagSet) Expand(lister func() ([]string, error),
+	created func(tag string) (time.Time, error)) ([]string, error) {
 
 	set := make(map[string]string)
+	dates := newDates(created)
 
 	if ts.NeedsExpansion() {
 
@@ -215,6 +339,11 @@ func (ts *TagSet) Expand(lister func() ([]string, error)) ([]string, error) {
 
 	log.Debugf("pruned tags: %v", pruned)
 
+	if ts.newerThan > 0 {
+		log.WithField("age", ts.newerThan).Debug("pruning tags by age")
+		ret = filterNewerThan(ret, ts.newerThan, dates)
+	}
+
 	// verbatim tags must not be pruned, but are subject to count limiting
 	if ts.HasVerbatim() {
 		log.Debugf("adding verbatim tags: %v", ts.verbatim)
@@ -224,6 +353,10 @@ func (ts *TagSet) Expand(lister func() ([]string, error)) ([]string, error) {
 	if ts.keepCount > 0 {
 		log.WithField("limit", ts.keepCount).Debug("reducing tag set")
 		ret = ts.reduce(ret, ts.keepCount)
+	} else if ts.keepNewest > 0 {
+		log.WithField("limit", ts.keepNewest).Debug(
+			"reducing tag set by creation date")
+		ret = reduceByDate(ret, ts.keepNewest, dates)
 	} else {
 		sort.Strings(ret)
 	}
@@ -249,6 +382,10 @@ func (ts *TagSet) expandSemver(tags []string) []string {
 
 	var ret []string
 	for _, v := range vers {
+		if len(v.Pre) > 0 && ts.prereleases == PrereleasesExclude {
+			log.WithField("tag", v.tag).Debug("skipping tag, pre-release")
+			continue
+		}
 		for _, r := range ts.semver {
 			if r(v.Version) {
 				ret = append(ret, v.tag)
@@ -261,34 +398,60 @@ func (ts *TagSet) expandSemver(tags []string) []string {
 	return ret
 }
 
-//
+// reduce limits tags to the latest limit tags, according to the tag set's
+// ordering, which defaults to semver
 func (ts *TagSet) reduce(tags []string, limit int) []string {
 
-	vers := make(versions, 0, len(tags))
+	order := ts.order
+	if _, ok := order.(*semverOrdering); ok || order == nil {
+		order = &semverOrdering{
+			preferStable: ts.prereleases == PrereleasesPreferStable}
+	}
+
+	type keyed struct {
+		key sortKey
+		tag string
+	}
+	ordered := make([]*keyed, 0, len(tags))
 
-	// reorg tags list to contain all non-semver tags on the left, all semvers
-	// on the right, which will then start at `pivot`
+	// reorg tags list to contain all tags not following the ordering on the
+	// left, all others on the right, which will then start at `pivot`
 	pivot := 0
 	for ix, t := range tags {
-		if v, err := semver.ParseTolerant(t); err != nil {
+		if k, ok := order.key(t); !ok {
 			if ix != pivot {
 				tags[pivot], tags[ix] = tags[ix], tags[pivot]
 			}
 			pivot++
 		} else {
-			vers = append(vers, &version{Version: v, tag: t})
+			ordered = append(ordered, &keyed{key: k, tag: t})
 		}
 	}
 
-	if len(vers) > 0 { // if there are semvers, we apply the limit only to those
-		if end := pivot + limit; end < len(tags) {
-			// there are more semvers than limit, need to reduce
-			vers.sort() // descending, semver
-			for ix, v := range vers {
-				tags[pivot+ix] = v.tag
+	if len(ordered) > 0 { // if there are ordered tags, limit only applies to them
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[j].key.less(ordered[i].key) // descending
+		})
+		// without `per`, all tags fall into the same bucket; variants of an
+		// already kept version are kept without counting
+		var removed []string
+		counts := make(map[string]int)
+		kept := make(map[string]bool)
+		tags = tags[:pivot]
+		for _, o := range ordered {
+			id := ts.identity(o.key)
+			if id != "" && kept[id] {
+				tags = append(tags, o.tag)
+			} else if b := ts.bucket(o.key); counts[b] < limit {
+				counts[b]++
+				kept[id] = true
+				tags = append(tags, o.tag)
+			} else {
+				removed = append(removed, o.tag)
 			}
-			log.Debugf("removed tags: %v", tags[end:])
-			tags = tags[:end]
+		}
+		if len(removed) > 0 {
+			log.Debugf("removed tags: %v", removed)
 		}
 		sort.Strings(tags) // ascending, string
 
@@ -303,6 +466,34 @@ func (ts *TagSet) reduce(tags []string, limit int) []string {
 	return tags
 }
 
+// identity returns the version of key without build metadata, if variants of a
+// version that differ only in build metadata are considered equivalent
+func (ts *TagSet) identity(key sortKey) string {
+	v, ok := key.(*semverKey)
+	if !ok || ts.buildMetadata != BuildMetadataEquivalent {
+		return ""
+	}
+	id := v.Version
+	id.Build = nil
+	return id.String()
+}
+
+// bucket returns the bucket for key when keeping the latest tags per major or
+// minor version
+func (ts *TagSet) bucket(key sortKey) string {
+	v, ok := key.(*semverKey)
+	if !ok {
+		return ""
+	}
+	switch ts.per {
+	case PerMajor:
+		return fmt.Sprintf("%d", v.Major)
+	case PerMinor:
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return ""
+}
+
 //
 func (ts *TagSet) expandRegex(tags []string) []string {
 
@@ -356,3 +547,23 @@ func isKeep(tag string) bool {
 func isKeepCount(tag string) bool {
 	return keepCount.Matches(tag)
 }
+
+//
+func isKeepNewest(tag string) bool {
+	return keepNewest.Matches(tag)
+}
+
+//
+func isNewerThan(tag string) bool {
+	return strings.HasPrefix(tag, NewerThanPrefix)
+}
+
+//
+func isPrereleases(tag string) bool {
+	return strings.HasPrefix(tag, PrereleasesPrefix)
+}
+
+//
+func isBuildMetadata(tag string) bool {
+	return strings.HasPrefix(tag, BuildMetadataPrefix)
+}
@@ -0,0 +1,150 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package tags
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// valid tag names according to the OCI distribution spec
+var validTag = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+// Transform describes how to rename tags when syncing them into the target.
+// The steps are applied in this order: regular expression replacement,
+// template, prefix & suffix. Each step is optional.
+type Transform struct {
+	Regex    string `yaml:"regex"`
+	Template string `yaml:"template"`
+	Prefix   string `yaml:"prefix"`
+	Suffix   string `yaml:"suffix"`
+	//
+	regex    *regexp.Regexp
+	replace  string
+	template *template.Template
+}
+
+// Validate compiles regular expression and template. It is safe to call on nil.
+func (t *Transform) Validate() error {
+
+	if t == nil {
+		return nil
+	}
+
+	if t.Regex == "" && t.Template == "" && t.Prefix == "" && t.Suffix == "" {
+		return fmt.Errorf("no transformation specified")
+	}
+
+	if t.Regex != "" {
+		parts := strings.SplitN(t.Regex, ",", 2)
+		if len(parts) < 2 {
+			return fmt.Errorf("replacement expression missing in 'regex'")
+		}
+		var err error
+		if t.regex, err = util.CompileRegex(parts[0], false); err != nil {
+			return fmt.Errorf(
+				"invalid regular expression '%s': %v", parts[0], err)
+		}
+		t.replace = parts[1]
+	}
+
+	if t.Template != "" {
+		var err error
+		if t.template, err = template.New("tag").Option(
+			"missingkey=error").Parse(t.Template); err != nil {
+			return fmt.Errorf("invalid template '%s': %v", t.Template, err)
+		}
+	}
+
+	return nil
+}
+
+// Apply transforms tag, which may be a plain tag, or a tag with digest as used
+// in sync options. The digest part is retained, a digest only tag is returned
+// unchanged. It is safe to call on nil, in which case tag is not changed.
+func (t *Transform) Apply(tag string) (string, error) {
+
+	if t == nil {
+		return tag, nil
+	}
+
+	name, digest := util.SplitTag(tag)
+	if name == "" {
+		return tag, nil
+	}
+
+	ret := name
+
+	if t.regex != nil {
+		ret = t.regex.ReplaceAllString(ret, t.replace)
+	}
+
+	if t.template != nil {
+		var buf bytes.Buffer
+		if err := t.template.Execute(
+			&buf, struct{ Tag string }{Tag: ret}); err != nil {
+			return "", fmt.Errorf(
+				"error transforming tag '%s': %v", name, err)
+		}
+		ret = buf.String()
+	}
+
+	ret = t.Prefix + ret + t.Suffix
+
+	if !validTag.MatchString(ret) {
+		return "", fmt.Errorf(
+			"tag '%s' transformed into invalid tag '%s'", name, ret)
+	}
+
+	return util.JoinTag(ret, digest), nil
+}
+
+// Check transforms all tags, and returns an error if any of them cannot be
+// transformed, or if several of them would end up as the same target tag.
+func (t *Transform) Check(tags []string) error {
+
+	if t == nil {
+		return nil
+	}
+
+	seen := make(map[string]string, len(tags))
+
+	for _, tag := range tags {
+		trgt, err := t.Apply(tag)
+		if err != nil {
+			return err
+		}
+		src, _ := util.SplitTag(tag)
+		name, _ := util.SplitTag(trgt)
+		if name == "" {
+			continue
+		}
+		if other, ok := seen[name]; ok && other != src {
+			return fmt.Errorf(
+				"tags '%s' and '%s' both transform into target tag '%s'",
+				other, src, name)
+		}
+		seen[name] = src
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package tags
+
+import (
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestTransform(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var nilTransform *Transform
+	th.AssertNoError(nilTransform.Validate())
+	tag, err := nilTransform.Apply("1.2.3")
+	th.AssertNoError(err)
+	th.AssertEqual("1.2.3", tag)
+
+	digest := "sha256:" +
+		"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	for _, tc := range []struct {
+		transform *Transform
+		tag       string
+		expected  string
+	}{
+		{&Transform{Prefix: "upstream-"}, "1.2.3", "upstream-1.2.3"},
+		{&Transform{Regex: "^v(.+)$,$1"}, "v1.2.3", "1.2.3"},
+		{&Transform{Regex: "^v(.+)$,$1"}, "1.2.3", "1.2.3"},
+		{&Transform{Template: "{{.Tag}}-mirror"}, "1.2.3", "1.2.3-mirror"},
+		{&Transform{Regex: "^v,", Template: "{{.Tag}}-mirror",
+			Prefix: "x-", Suffix: "-y"}, "v1.2.3", "x-1.2.3-mirror-y"},
+		{&Transform{Prefix: "upstream-"}, "1.2.3@" + digest,
+			"upstream-1.2.3@" + digest},
+		{&Transform{Prefix: "upstream-"}, digest, digest},
+	} {
+		th.AssertNoError(tc.transform.Validate())
+		tag, err := tc.transform.Apply(tc.tag)
+		th.AssertNoError(err)
+		th.AssertEqual(tc.expected, tag)
+	}
+
+	th.AssertError((&Transform{}).Validate(), "no transformation specified")
+	th.AssertError((&Transform{Regex: "^v"}).Validate(),
+		"replacement expression missing")
+	th.AssertError((&Transform{Template: "{{.Tag"}).Validate(),
+		"invalid template")
+
+	tr := &Transform{Suffix: "/bad"}
+	th.AssertNoError(tr.Validate())
+	_, err = tr.Apply("1.2.3")
+	th.AssertError(err, "transformed into invalid tag '1.2.3/bad'")
+
+	tr = &Transform{Regex: "^v,"}
+	th.AssertNoError(tr.Validate())
+	th.AssertNoError(tr.Check([]string{"v1.0.0", "1.1.0"}))
+	th.AssertError(tr.Check([]string{"v1.0.0", "1.1.0", "1.0.0"}),
+		"tags 'v1.0.0' and '1.0.0' both transform into target tag '1.0.0'")
+}
@@ -0,0 +1,107 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gocrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// NewKey creates a key for signing images.
+func (t *TestHelper) NewKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	t.AssertNoError(err)
+	return key
+}
+
+// WritePublicKey writes the public part of key as PEM to file name in dir, and
+// returns the file's path.
+func (t *TestHelper) WritePublicKey(dir, name string,
+	key *ecdsa.PrivateKey) string {
+
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	t.AssertNoError(err)
+	file := filepath.Join(dir, name)
+	t.AssertNoError(os.WriteFile(file, pem.EncodeToMemory(
+		&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	return file
+}
+
+// Sign signs the SHA256 hash of data with key.
+func (t *TestHelper) Sign(key crypto.Signer, data []byte) []byte {
+	hash := sha256.Sum256(data)
+	sig, err := key.Sign(rand.Reader, hash[:], crypto.SHA256)
+	t.AssertNoError(err)
+	return sig
+}
+
+// PushSignature pushes a cosign style signature image for the image with the
+// given digest to repo, and returns the signature's tag. If claimed is set,
+// the payload refers to that digest instead. Annotation functions can add
+// further annotations to the signature layer.
+func (t *TestHelper) PushSignature(repo string, digest gocrv1.Hash,
+	key crypto.Signer, claimed *gocrv1.Hash,
+	annotate ...func(payload, sig []byte) map[string]string) string {
+
+	if claimed == nil {
+		claimed = &digest
+	}
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{`+
+		`"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},`+
+		`"type":"cosign container image signature"},"optional":null}`,
+		repo, claimed))
+	sig := t.Sign(key, payload)
+
+	anns := map[string]string{
+		"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(
+			sig)}
+	for _, a := range annotate {
+		for k, v := range a(payload, sig) {
+			anns[k] = v
+		}
+	}
+
+	img, err := mutate.Append(
+		mutate.MediaType(empty.Image, types.OCIManifestSchema1),
+		mutate.Addendum{
+			Layer: static.NewLayer(payload,
+				"application/vnd.dev.cosign.simplesigning.v1+json"),
+			Annotations: anns,
+		})
+	t.AssertNoError(err)
+
+	tag := strings.Replace(digest.String(), ":", "-", 1) + ".sig"
+	t.PushImage(fmt.Sprintf("%s:%s", repo, tag), img)
+	return tag
+}
@@ -0,0 +1,143 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package test
+
+import (
+	"net/http/httptest"
+	"strings"
+
+	gocrname "github.com/google/go-containerregistry/pkg/name"
+	gocrregistry "github.com/google/go-containerregistry/pkg/registry"
+	gocrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	gocrremote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// NewRegistry starts an in-process registry, and returns its host:port. The
+// registry is shut down when the test completes.
+func (t *TestHelper) NewRegistry(referrers bool) string {
+	srv := httptest.NewServer(
+		gocrregistry.New(gocrregistry.WithReferrersSupport(referrers)))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+// RandomImage creates a random OCI image, optionally with a subject.
+func (t *TestHelper) RandomImage(subject *gocrv1.Descriptor) gocrv1.Image {
+	img, err := random.Image(256, 1)
+	if err != nil {
+		t.Fatalf("cannot create random image: %v", err)
+	}
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, types.OCIConfigJSON)
+	if subject != nil {
+		img = mutate.Subject(img, *subject).(gocrv1.Image)
+	}
+	return img
+}
+
+// PushImage pushes img to ref, and returns the image's descriptor.
+func (t *TestHelper) PushImage(ref string, img gocrv1.Image) *gocrv1.Descriptor {
+
+	r, err := gocrname.ParseReference(ref)
+	if err != nil {
+		t.Fatalf("invalid reference '%s': %v", ref, err)
+	}
+	if err := gocrremote.Write(r, img); err != nil {
+		t.Fatalf("cannot push image '%s': %v", ref, err)
+	}
+
+	d, err := img.Digest()
+	if err != nil {
+		t.Fatalf("cannot get image digest: %v", err)
+	}
+	s, err := img.Size()
+	if err != nil {
+		t.Fatalf("cannot get image size: %v", err)
+	}
+	mt, err := img.MediaType()
+	if err != nil {
+		t.Fatalf("cannot get image media type: %v", err)
+	}
+
+	return &gocrv1.Descriptor{MediaType: mt, Digest: d, Size: s}
+}
+
+// HasManifest checks whether ref can be found in the registry.
+func (t *TestHelper) HasManifest(ref string) bool {
+	r, err := gocrname.ParseReference(ref)
+	if err != nil {
+		t.Fatalf("invalid reference '%s': %v", ref, err)
+	}
+	_, err = gocrremote.Head(r)
+	return err == nil
+}
+
+// PushIndex pushes a multi-platform index with a random image for each of the
+// given platforms to ref.
+func (t *TestHelper) PushIndex(ref string, platforms ...string) {
+
+	var adds []mutate.IndexAddendum
+	for _, p := range platforms {
+		pl, err := gocrv1.ParsePlatform(p)
+		if err != nil {
+			t.Fatalf("invalid platform '%s': %v", p, err)
+		}
+		adds = append(adds, mutate.IndexAddendum{
+			Add:        t.RandomImage(nil),
+			Descriptor: gocrv1.Descriptor{Platform: pl},
+		})
+	}
+	idx := mutate.AppendManifests(
+		mutate.IndexMediaType(empty.Index, types.OCIImageIndex), adds...)
+
+	r, err := gocrname.ParseReference(ref)
+	if err != nil {
+		t.Fatalf("invalid reference '%s': %v", ref, err)
+	}
+	if err := gocrremote.WriteIndex(r, idx); err != nil {
+		t.Fatalf("cannot push index '%s': %v", ref, err)
+	}
+}
+
+// IndexPlatforms returns the platforms contained in the index at ref.
+func (t *TestHelper) IndexPlatforms(ref string) []string {
+
+	r, err := gocrname.ParseReference(ref)
+	if err != nil {
+		t.Fatalf("invalid reference '%s': %v", ref, err)
+	}
+	idx, err := gocrremote.Index(r)
+	if err != nil {
+		t.Fatalf("cannot get index '%s': %v", ref, err)
+	}
+	man, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatalf("cannot get index manifest '%s': %v", ref, err)
+	}
+
+	var ret []string
+	for _, m := range man.Manifests {
+		if m.Platform != nil {
+			ret = append(ret, m.Platform.String())
+		}
+	}
+	return ret
+}
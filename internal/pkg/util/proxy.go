@@ -0,0 +1,173 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Proxy holds the HTTP proxy settings of a location. A nil Proxy, or one with
+// an empty URL, means that the proxy settings from the environment are used.
+type Proxy struct {
+	URL     string
+	NoProxy string
+	//
+	url *url.URL
+}
+
+//
+func NewProxy(proxy, noProxy string) (*Proxy, error) {
+
+	ret := &Proxy{URL: strings.TrimSpace(proxy), NoProxy: noProxy}
+
+	if ret.URL == "" {
+		if strings.TrimSpace(noProxy) != "" {
+			return nil, fmt.Errorf("'no-proxy' requires 'proxy' to be set")
+		}
+		return ret, nil
+	}
+
+	u, err := url.Parse(ret.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL '%s': %v", ret.URL, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf(
+			"invalid proxy URL '%s': scheme must be http, https, or socks5",
+			ret.URL)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL '%s': no host", ret.URL)
+	}
+
+	ret.url = u
+	return ret, nil
+}
+
+//
+func (p *Proxy) IsSet() bool {
+	return p != nil && p.url != nil
+}
+
+// Func returns a proxy selection function suitable for http.Transport.
+func (p *Proxy) Func() func(*http.Request) (*url.URL, error) {
+
+	if !p.IsSet() {
+		return http.ProxyFromEnvironment
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if p.bypass(req.URL.Host) {
+			return nil, nil
+		}
+		return p.url, nil
+	}
+}
+
+// Transport returns a clone of the default HTTP transport, configured to use
+// this proxy, and optionally skip TLS verification.
+func (p *Proxy) Transport(skipTLSVerify bool) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = p.Func()
+	if skipTLSVerify {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+	return t
+}
+
+// Client returns an HTTP client using Transport.
+func (p *Proxy) Client(skipTLSVerify bool) *http.Client {
+	return &http.Client{Transport: p.Transport(skipTLSVerify)}
+}
+
+// Env returns the environment variables for passing this proxy to a child
+// process. If the proxy is not set, nil is returned, so that the child process
+// inherits the environment as is.
+func (p *Proxy) Env() []string {
+	if !p.IsSet() {
+		return nil
+	}
+	var ret []string
+	for _, k := range []string{"HTTP_PROXY", "HTTPS_PROXY"} {
+		ret = append(ret, fmt.Sprintf("%s=%s", k, p.URL),
+			fmt.Sprintf("%s=%s", strings.ToLower(k), p.URL))
+	}
+	return append(ret, fmt.Sprintf("NO_PROXY=%s", p.NoProxy),
+		fmt.Sprintf("no_proxy=%s", p.NoProxy))
+}
+
+// bypass checks whether host matches any of the no-proxy entries. Entries are
+// separated by comma, and may be host names, domain suffixes (with or without
+// leading dot), IP addresses, CIDR ranges, or `*` for bypassing altogether.
+// Entries may specify a port, in which case the port must match as well.
+func (p *Proxy) bypass(host string) bool {
+
+	h, port, err := net.SplitHostPort(host)
+	if err != nil {
+		h = host
+		port = ""
+	}
+	h = strings.ToLower(strings.Trim(h, "[]"))
+	ip := net.ParseIP(h)
+
+	for _, e := range strings.Split(p.NoProxy, ",") {
+
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if e == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(e); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		eh, ep, err := net.SplitHostPort(e)
+		if err != nil {
+			eh = e
+			ep = ""
+		}
+		if ep != "" && ep != port {
+			continue
+		}
+
+		eh = strings.Trim(eh, "[]")
+		if h == strings.TrimPrefix(eh, ".") ||
+			strings.HasSuffix(h, "."+strings.TrimPrefix(eh, ".")) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,99 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package util
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestProxyValidation(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	p, err := NewProxy("", "")
+	th.AssertNoError(err)
+	th.AssertFalse(p.IsSet())
+
+	_, err = NewProxy("", "internal.acme.com")
+	th.AssertError(err, "'no-proxy' requires 'proxy' to be set")
+
+	_, err = NewProxy("ftp://proxy.acme.com", "")
+	th.AssertError(err, "scheme must be http, https, or socks5")
+
+	p, err = NewProxy("http://proxy.acme.com:3128", "")
+	th.AssertNoError(err)
+	th.AssertTrue(p.IsSet())
+}
+
+//
+func TestProxyBypass(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	p, err := NewProxy("http://proxy.acme.com:3128",
+		"registry.acme.com, .internal.acme.com,10.0.0.0/8,other.acme.com:5000")
+	th.AssertNoError(err)
+
+	for host, want := range map[string]bool{
+		"registry.acme.com":          true,
+		"registry.acme.com:443":      true,
+		"a.internal.acme.com":        true,
+		"internal.acme.com":          true,
+		"10.1.2.3:5000":              true,
+		"other.acme.com:5000":        true,
+		"other.acme.com":             false,
+		"docker.io":                  false,
+		"notregistry.acme.com":       false,
+		"registry.acme.com.evil.com": false,
+	} {
+		req, _ := http.NewRequest("GET", "https://"+host+"/v2/", nil)
+		u, err := p.Func()(req)
+		th.AssertNoError(err)
+		if want {
+			th.AssertNil(u)
+		} else {
+			th.AssertEqual("http://proxy.acme.com:3128", u.String())
+		}
+	}
+
+	p, err = NewProxy("http://proxy.acme.com:3128", "*")
+	th.AssertNoError(err)
+	th.AssertTrue(p.bypass("docker.io"))
+}
+
+//
+func TestProxyEnv(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	var p *Proxy
+	th.AssertEqual(0, len(p.Env()))
+
+	p, _ = NewProxy("http://proxy.acme.com:3128", "registry.acme.com")
+	th.AssertEquivalentSlices([]string{
+		"HTTP_PROXY=http://proxy.acme.com:3128",
+		"http_proxy=http://proxy.acme.com:3128",
+		"HTTPS_PROXY=http://proxy.acme.com:3128",
+		"https_proxy=http://proxy.acme.com:3128",
+		"NO_PROXY=registry.acme.com",
+		"no_proxy=registry.acme.com",
+	}, p.Env())
+}
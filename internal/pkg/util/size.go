@@ -0,0 +1,93 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size is a number of bytes. In YAML, it can be given as a plain number, or
+// with a unit, e.g. `500MB` or `2GiB`.
+type Size int64
+
+// size units, decimal and binary
+var sizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"KB":  1000,
+	"MB":  1000 * 1000,
+	"GB":  1000 * 1000 * 1000,
+	"TB":  1000 * 1000 * 1000 * 1000,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+}
+
+// ParseSize parses a size such as `2GiB`
+func ParseSize(s string) (Size, error) {
+
+	s = strings.TrimSpace(s)
+	ix := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if ix == -1 {
+		ix = len(s)
+	}
+
+	unit, ok := sizeUnits[strings.ToUpper(strings.TrimSpace(s[ix:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit in size '%s'", s)
+	}
+
+	n, err := strconv.ParseFloat(s[:ix], 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", s)
+	}
+
+	return Size(n * float64(unit)), nil
+}
+
+//
+func (s *Size) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	size, err := ParseSize(str)
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// String returns the size in human readable form, using binary units
+func (s Size) String() string {
+	units := []string{"KiB", "MiB", "GiB", "TiB"}
+	if s < 1<<10 {
+		return fmt.Sprintf("%dB", int64(s))
+	}
+	v := float64(s) / (1 << 10)
+	u := 0
+	for ; v >= 1<<10 && u < len(units)-1; u++ {
+		v /= 1 << 10
+	}
+	return fmt.Sprintf("%.1f%s", v, units[u])
+}
@@ -0,0 +1,57 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestSize(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	for _, tc := range []struct {
+		yaml     string
+		expected Size
+	}{
+		{"size: 1024", 1024},
+		{"size: 500MB", 500 * 1000 * 1000},
+		{"size: 2GiB", 2 << 30},
+		{"size: 1.5 kib", 1536},
+		{"size: 3tb", 3 * 1000 * 1000 * 1000 * 1000},
+	} {
+		var s struct {
+			Size Size `yaml:"size"`
+		}
+		th.AssertNoError(yaml.Unmarshal([]byte(tc.yaml), &s))
+		th.AssertEqual(tc.expected, s.Size)
+	}
+
+	for _, s := range []string{"2XB", "GiB", "-1MB", "1.2.3"} {
+		_, err := ParseSize(s)
+		th.AssertNotNil(err)
+	}
+
+	th.AssertEqual("512B", Size(512).String())
+	th.AssertEqual("2.0GiB", Size(2<<30).String())
+	th.AssertEqual("1.5MiB", Size(1536<<10).String())
+}
@@ -0,0 +1,167 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/util"
+)
+
+// annotations cosign attaches to the layers of a signature image
+const (
+	annotationSignature   = "dev.cosignproject.cosign/signature"
+	annotationCertificate = "dev.sigstore.cosign/certificate"
+	annotationChain       = "dev.sigstore.cosign/chain"
+	annotationBundle      = "dev.sigstore.cosign/bundle"
+)
+
+//
+const mediaTypeSimpleSigning = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+// signature is a single cosign signature, i.e. one layer of a signature image
+type signature struct {
+	payload   []byte
+	signature []byte
+	cert      string
+	chain     string
+	bundle    string
+}
+
+// simpleSigning is the payload format signed by cosign
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// fetchSignatures retrieves all cosign signatures for the image with the given
+// digest in repo. If there are none, an empty list is returned.
+func fetchSignatures(src *registry.Remote, repo, digest string) (
+	[]*signature, error) {
+
+	ref := util.JoinRefAndTag(repo, registry.CosignTag(digest, ".sig"))
+
+	img, err := src.Image(ref)
+	if err != nil {
+		if registry.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot get signatures '%s': %v", ref, err)
+	}
+
+	man, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get signature manifest: %v", err)
+	}
+
+	var ret []*signature
+
+	for _, l := range man.Layers {
+
+		if string(l.MediaType) != mediaTypeSimpleSigning {
+			continue
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(
+			l.Annotations[annotationSignature])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+
+		layer, err := img.LayerByDigest(l.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get signature payload: %v", err)
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get signature payload: %v", err)
+		}
+		payload, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read signature payload: %v", err)
+		}
+
+		ret = append(ret, &signature{
+			payload:   payload,
+			signature: sig,
+			cert:      l.Annotations[annotationCertificate],
+			chain:     l.Annotations[annotationChain],
+			bundle:    l.Annotations[annotationBundle],
+		})
+	}
+
+	return ret, nil
+}
+
+// checkPayload makes sure the signed payload refers to the image digest, so
+// that a signature cannot be replayed for a different image
+func (s *signature) checkPayload(digest string) error {
+
+	var p simpleSigning
+	if err := json.Unmarshal(s.payload, &p); err != nil {
+		return fmt.Errorf("invalid signature payload: %v", err)
+	}
+
+	if d := p.Critical.Image.DockerManifestDigest; d != digest {
+		return fmt.Errorf("signature is for digest '%s'", d)
+	}
+	return nil
+}
+
+// verifySignature verifies sig over data with key
+func verifySignature(key crypto.PublicKey, data, sig []byte) error {
+
+	hash := sha256.Sum256(data)
+
+	switch k := key.(type) {
+
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(k, hash[:], sig) {
+			return nil
+		}
+
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil {
+			return nil
+		}
+
+	case ed25519.PublicKey:
+		if ed25519.Verify(k, data, sig) {
+			return nil
+		}
+
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	return errors.New("invalid signature")
+}
@@ -0,0 +1,204 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Fulcio certificate extensions holding the OIDC issuer; the first one is
+// DER encoded, the second one deprecated and holding the raw string
+var (
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+)
+
+// bundle is the Rekor transparency log entry cosign attaches to a signature
+type bundle struct {
+	SignedEntryTimestamp []byte        `json:"SignedEntryTimestamp"`
+	Payload              bundlePayload `json:"Payload"`
+}
+
+// bundlePayload is what the signed entry timestamp is computed over; fields
+// must stay in this order to yield the canonical JSON encoding
+type bundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// hashedRekord is the log entry type for signatures over artifact hashes
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content []byte `json:"content"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// checkKeyless verifies a signature made with a short-lived Fulcio certificate.
+// Since the certificate has long expired by the time we look at it, we rely on
+// the Rekor bundle for the point in time at which it was valid.
+func (p *Policy) checkKeyless(s *signature) error {
+
+	if s.cert == "" {
+		return errors.New("signature has no certificate")
+	}
+	if s.bundle == "" {
+		return errors.New("signature has no transparency log bundle")
+	}
+
+	cert, err := parseCert([]byte(s.cert))
+	if err != nil {
+		return err
+	}
+
+	var b bundle
+	if err := json.Unmarshal([]byte(s.bundle), &b); err != nil {
+		return fmt.Errorf("invalid bundle: %v", err)
+	}
+
+	if err := p.checkBundle(&b, s); err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	for rest := []byte(s.chain); len(rest) > 0; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if c, err := x509.ParseCertificate(block.Bytes); err == nil {
+			intermediates.AddCert(c)
+		}
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         p.roots,
+		Intermediates: intermediates,
+		CurrentTime:   time.Unix(b.Payload.IntegratedTime, 0),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("untrusted certificate: %v", err)
+	}
+
+	if err := p.checkIdentity(cert); err != nil {
+		return err
+	}
+
+	return verifySignature(cert.PublicKey, s.payload, s.signature)
+}
+
+// checkBundle verifies the signed entry timestamp with the Rekor key, and that
+// the log entry is actually about this signature
+func (p *Policy) checkBundle(b *bundle, s *signature) error {
+
+	canonical, err := json.Marshal(b.Payload)
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(
+		p.rekorKey, canonical, b.SignedEntryTimestamp); err != nil {
+		return fmt.Errorf("invalid signed entry timestamp: %v", err)
+	}
+
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return fmt.Errorf("invalid bundle body: %v", err)
+	}
+
+	var entry hashedRekord
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return fmt.Errorf("invalid bundle body: %v", err)
+	}
+
+	hash := sha256.Sum256(s.payload)
+	if entry.Kind != "hashedrekord" ||
+		entry.Spec.Data.Hash.Algorithm != "sha256" ||
+		entry.Spec.Data.Hash.Value != hex.EncodeToString(hash[:]) ||
+		!bytes.Equal(entry.Spec.Signature.Content, s.signature) {
+		return errors.New("transparency log entry does not match signature")
+	}
+
+	return nil
+}
+
+//
+func (p *Policy) checkIdentity(cert *x509.Certificate) error {
+
+	issuer := ""
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidIssuerV2) {
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err != nil {
+				return fmt.Errorf("invalid issuer extension: %v", err)
+			}
+			break
+		}
+		if ext.Id.Equal(oidIssuerV1) {
+			issuer = string(ext.Value)
+		}
+	}
+
+	if !p.issuer.MatchString(issuer) {
+		return fmt.Errorf("issuer '%s' not accepted", issuer)
+	}
+
+	var ids []string
+	ids = append(ids, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		ids = append(ids, u.String())
+	}
+
+	for _, id := range ids {
+		if p.identity.MatchString(id) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("identities %v not accepted", ids)
+}
+
+//
+func parseCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data in certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %v", err)
+	}
+	return cert, nil
+}
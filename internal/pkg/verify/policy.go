@@ -0,0 +1,207 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package verify
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+)
+
+// PolicyError is returned when an image does not satisfy a verification
+// policy, as opposed to errors that prevented verification altogether.
+type PolicyError struct {
+	Ref    string
+	Reason string
+}
+
+//
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("image '%s' violates signature policy: %s",
+		e.Ref, e.Reason)
+}
+
+//
+func IsPolicyError(err error) bool {
+	var pe *PolicyError
+	return errors.As(err, &pe)
+}
+
+// Policy describes how source images need to be signed with cosign in order
+// to get synced. Either a public key is given, or the keyless identity
+// constraints together with the trust material for checking them.
+type Policy struct {
+	Key      string `yaml:"key"`
+	Identity string `yaml:"identity"`
+	Issuer   string `yaml:"issuer"`
+	Roots    string `yaml:"roots"`
+	RekorKey string `yaml:"rekor-key"`
+	//
+	pubKey   crypto.PublicKey
+	identity *regexp.Regexp
+	issuer   *regexp.Regexp
+	roots    *x509.CertPool
+	rekorKey crypto.PublicKey
+}
+
+//
+func (p *Policy) Validate() error {
+
+	if p == nil {
+		return nil
+	}
+
+	if p.Key != "" {
+		if p.Identity != "" || p.Issuer != "" {
+			return errors.New(
+				"use either 'key', or 'identity' & 'issuer' for keyless")
+		}
+		var err error
+		if p.pubKey, err = loadPublicKey(p.Key); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if p.Identity == "" || p.Issuer == "" {
+		return errors.New(
+			"requires either 'key', or 'identity' & 'issuer' for keyless")
+	}
+
+	if p.Roots == "" || p.RekorKey == "" {
+		return errors.New("keyless requires 'roots' and 'rekor-key'")
+	}
+
+	var err error
+
+	if p.identity, err = compileWhole(p.Identity); err != nil {
+		return fmt.Errorf("invalid identity expression: %v", err)
+	}
+	if p.issuer, err = compileWhole(p.Issuer); err != nil {
+		return fmt.Errorf("invalid issuer expression: %v", err)
+	}
+	if p.roots, err = loadCertPool(p.Roots); err != nil {
+		return err
+	}
+	if p.rekorKey, err = loadPublicKey(p.RekorKey); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//
+func (p *Policy) IsKeyless() bool {
+	return p.Key == ""
+}
+
+// Verify checks whether the image at ref carries a valid cosign signature
+// according to this policy, and returns the verified manifest digest. The
+// signature is looked up via src in repository repo.
+func (p *Policy) Verify(src *registry.Remote, repo, ref string) (
+	string, error) {
+
+	digest, err := src.Digest(ref)
+	if err != nil {
+		return "", fmt.Errorf("cannot get digest of '%s': %v", ref, err)
+	}
+
+	sigs, err := fetchSignatures(src, repo, digest)
+	if err != nil {
+		return "", err
+	}
+	if len(sigs) == 0 {
+		return "", &PolicyError{Ref: ref, Reason: "no signature found"}
+	}
+
+	var reasons []string
+	for _, s := range sigs {
+		if err := p.check(s, digest); err != nil {
+			reasons = append(reasons, err.Error())
+			continue
+		}
+		log.WithFields(log.Fields{"ref": ref, "digest": digest}).Info(
+			"signature verified")
+		return digest, nil
+	}
+
+	return "", &PolicyError{Ref: ref, Reason: strings.Join(reasons, "; ")}
+}
+
+//
+func (p *Policy) check(s *signature, digest string) error {
+
+	if err := s.checkPayload(digest); err != nil {
+		return err
+	}
+
+	if p.IsKeyless() {
+		return p.checkKeyless(s)
+	}
+
+	return verifySignature(p.pubKey, s.payload, s.signature)
+}
+
+//
+func loadPublicKey(file string) (crypto.PublicKey, error) {
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read public key: %v", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in public key file '%s'", file)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in '%s': %v", file, err)
+	}
+	return key, nil
+}
+
+//
+func loadCertPool(file string) (*x509.CertPool, error) {
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read root certificates: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in '%s'", file)
+	}
+	return pool, nil
+}
+
+// compileWhole compiles expr to only match whole strings; the expression is
+// grouped before anchoring, so that each branch of an alternation is anchored
+func compileWhole(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + expr + ")$")
+}
@@ -0,0 +1,215 @@
+/*
+	Copyright 2026 Alexander Vollschwitz <xelalex@gmx.net>
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	  http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package verify
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xelalexv/dregsy/internal/pkg/registry"
+	"github.com/xelalexv/dregsy/internal/pkg/test"
+)
+
+//
+func TestVerifyWithKey(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	dir := t.TempDir()
+
+	key := th.NewKey()
+	other := th.NewKey()
+
+	p := &Policy{Key: th.WritePublicKey(dir, "cosign.pub", key)}
+	th.AssertNoError(p.Validate())
+
+	repo := th.NewRegistry(false) + "/acme/app"
+	src := registry.NewRemote("", false, nil)
+
+	signed := th.PushImage(repo+":signed", th.RandomImage(nil))
+	th.PushSignature(repo, signed.Digest, key, nil)
+
+	digest, err := p.Verify(src, repo, repo+":signed")
+	th.AssertNoError(err)
+	th.AssertEqual(signed.Digest.String(), digest)
+
+	th.PushImage(repo+":unsigned", th.RandomImage(nil))
+	_, err = p.Verify(src, repo, repo+":unsigned")
+	th.AssertError(err, "no signature found")
+	th.AssertTrue(IsPolicyError(err))
+
+	missigned := th.PushImage(repo+":missigned", th.RandomImage(nil))
+	th.PushSignature(repo, missigned.Digest, other, nil)
+	_, err = p.Verify(src, repo, repo+":missigned")
+	th.AssertError(err, "invalid signature")
+	th.AssertTrue(IsPolicyError(err))
+
+	// signature of a different image copied over to this one
+	replayed := th.PushImage(repo+":replayed", th.RandomImage(nil))
+	th.PushSignature(repo, replayed.Digest, key, &signed.Digest)
+	_, err = p.Verify(src, repo, repo+":replayed")
+	th.AssertError(err, "signature is for digest")
+}
+
+//
+func TestVerifyKeyless(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+	dir := t.TempDir()
+
+	caKey := th.NewKey()
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-fulcio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(
+		rand.Reader, ca, ca, caKey.Public(), caKey)
+	th.AssertNoError(err)
+	ca, _ = x509.ParseCertificate(caDER)
+
+	roots := filepath.Join(dir, "roots.pem")
+	th.AssertNoError(ioutil.WriteFile(roots, pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+
+	rekorKey := th.NewKey()
+
+	p := &Policy{
+		// alternations need to be anchored as a whole
+		Identity: "release@acme\\.com|ops@acme\\.com",
+		Issuer:   "https://accounts\\.acme\\.com|https://sso\\.acme\\.com",
+		Roots:    roots,
+		RekorKey: th.WritePublicKey(dir, "rekor.pub", rekorKey),
+	}
+	th.AssertNoError(p.Validate())
+
+	repo := th.NewRegistry(false) + "/acme/app"
+	src := registry.NewRemote("", false, nil)
+
+	for _, tc := range []struct {
+		email  string
+		issuer string
+		err    string
+	}{
+		{"release@acme.com", "https://accounts.acme.com", ""},
+		{"mallory@evil.com", "https://accounts.acme.com", "not accepted"},
+		{"release@acme.com", "https://accounts.evil.com", "not accepted"},
+		{"ops@acme.com", "https://sso.acme.com", ""},
+		{"mallory-ops@acme.com", "https://sso.acme.com", "not accepted"},
+		{"release@acme.com", "https://accounts.acme.com.evil.com",
+			"not accepted"},
+	} {
+
+		signer := th.NewKey()
+		issuer, _ := asn1.Marshal(tc.issuer)
+		leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber:   big.NewInt(2),
+			NotBefore:      time.Now().Add(-time.Minute),
+			NotAfter:       time.Now().Add(10 * time.Minute),
+			KeyUsage:       x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+			EmailAddresses: []string{tc.email},
+			ExtraExtensions: []pkix.Extension{
+				{Id: oidIssuerV2, Value: issuer}},
+		}, ca, signer.Public(), caKey)
+		th.AssertNoError(err)
+
+		img := th.PushImage(repo+":latest", th.RandomImage(nil))
+		th.PushSignature(repo, img.Digest, signer, nil,
+			func(payload, sig []byte) map[string]string {
+				return map[string]string{
+					annotationCertificate: string(pem.EncodeToMemory(
+						&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})),
+					annotationBundle: newBundle(th, rekorKey, payload, sig),
+				}
+			})
+
+		digest, err := p.Verify(src, repo, repo+":latest")
+		if tc.err == "" {
+			th.AssertNoError(err)
+			th.AssertEqual(img.Digest.String(), digest)
+		} else {
+			th.AssertError(err, tc.err)
+		}
+	}
+}
+
+//
+func TestInvalidPolicies(t *testing.T) {
+
+	th := test.NewTestHelper(t)
+
+	th.AssertError((&Policy{}).Validate(), "requires either 'key'")
+	th.AssertError((&Policy{Key: "k", Identity: "i"}).Validate(),
+		"use either 'key'")
+	th.AssertError((&Policy{Identity: "i", Issuer: "i"}).Validate(),
+		"keyless requires 'roots' and 'rekor-key'")
+	th.AssertError((&Policy{Key: "/does/not/exist"}).Validate(),
+		"cannot read public key")
+}
+
+//
+func newBundle(th *test.TestHelper, rekorKey crypto.Signer, payload,
+	sig []byte) string {
+
+	hash := sha256.Sum256(payload)
+	body, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]interface{}{
+			"data": map[string]interface{}{
+				"hash": map[string]string{
+					"algorithm": "sha256",
+					"value":     hex.EncodeToString(hash[:]),
+				},
+			},
+			"signature": map[string]interface{}{"content": sig},
+		},
+	})
+	th.AssertNoError(err)
+
+	b := bundle{Payload: bundlePayload{
+		Body:           base64.StdEncoding.EncodeToString(body),
+		IntegratedTime: time.Now().Unix(),
+		LogID:          "test",
+		LogIndex:       1,
+	}}
+
+	canonical, err := json.Marshal(b.Payload)
+	th.AssertNoError(err)
+	b.SignedEntryTimestamp = th.Sign(rekorKey, canonical)
+
+	ret, err := json.Marshal(b)
+	th.AssertNoError(err)
+	return string(ret)
+}